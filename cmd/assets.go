@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+// assetChecker collects local images and attachments linked from Markdown.
+// The Notion MCP server has no way to upload files, so those links are sent
+// as written and won't resolve on Notion. It is safe for concurrent use by a
+// directory sync.
+type assetChecker struct {
+	mu     sync.Mutex
+	assets map[string][]string // by linking file
+}
+
+func newAssetChecker() *assetChecker {
+	return &assetChecker{assets: map[string][]string{}}
+}
+
+// check records the local files other than Markdown linked from body, which
+// belongs to file.
+func (a *assetChecker) check(file, body string) {
+	if a == nil {
+		return
+	}
+
+	var assets []string
+	for _, link := range cli.LocalLinks(body, filepath.Dir(file)) {
+		if !link.IsMarkdown() {
+			assets = append(assets, link.Target)
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(assets) > 0 {
+		a.assets[file] = assets
+	} else {
+		delete(a.assets, file)
+	}
+}
+
+// report prints a warning listing every file with local assets.
+func (a *assetChecker) report() {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.assets) == 0 {
+		return
+	}
+
+	files := make([]string, 0, len(a.assets))
+	for file := range a.assets {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Local images and attachments in %d %s can't be uploaded through the Notion MCP server and were left as relative links:", len(files), output.Plural("file", len(files)))
+	for _, file := range files {
+		fmt.Fprintf(&b, "\n  %s: %s", file, strings.Join(a.assets[file], ", "))
+	}
+	output.PrintWarning(b.String())
+}
@@ -69,16 +69,28 @@ func extractRichText(richText []mcp.RichText) string {
 
 type CommentCreateCmd struct {
 	PageID  string `arg:"" help:"Page ID or URL"`
-	Content string `help:"Comment content" short:"c" required:""`
+	Content string `help:"Comment content (- reads stdin)" short:"c" xor:"content" required:""`
+	File    string `help:"Read comment content from a file (- reads stdin)" short:"f" type:"existingfile" xor:"content" required:""`
 	JSON    bool   `help:"Output as JSON" short:"j"`
 }
 
 func (c *CommentCreateCmd) Run(ctx *Context) error {
 	ctx.JSON = c.JSON
-	return runCommentCreate(ctx, c.PageID, c.Content)
+	return runCommentCreate(ctx, c.PageID, c.Content, c.File)
 }
 
-func runCommentCreate(ctx *Context, pageID, content string) error {
+func runCommentCreate(ctx *Context, pageID, content, file string) error {
+	var err error
+	if file != "" {
+		content, err = cli.ReadContentFile(file)
+	} else {
+		content, err = cli.ReadContent(content)
+	}
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
 	client, err := cli.RequireClient()
 	if err != nil {
 		return err
@@ -2,8 +2,6 @@ package cmd
 
 import (
 	"context"
-	"os"
-	"strings"
 
 	"github.com/lox/notion-cli/internal/cli"
 	"github.com/lox/notion-cli/internal/mcp"
@@ -11,9 +9,11 @@ import (
 )
 
 type DBCmd struct {
-	List   DBListCmd   `cmd:"" help:"List databases"`
-	Query  DBQueryCmd  `cmd:"" help:"Query a database"`
-	Create DBCreateCmd `cmd:"" help:"Create an entry in a database"`
+	List    DBListCmd    `cmd:"" help:"List databases"`
+	Query   DBQueryCmd   `cmd:"" help:"Query a database"`
+	Create  DBCreateCmd  `cmd:"" help:"Create an entry in a database"`
+	Archive DBArchiveCmd `cmd:"" help:"Archive (trash) database entries"`
+	Restore DBRestoreCmd `cmd:"" help:"Restore archived database entries"`
 }
 
 type DBListCmd struct {
@@ -81,26 +81,77 @@ func (c *DBQueryCmd) Run(ctx *Context) error {
 
 type DBCreateCmd struct {
 	Database string   `arg:"" help:"Database URL, ID, or name"`
-	Title    string   `help:"Entry title" short:"t" required:""`
+	Title    string   `help:"Entry title (default: title from --file frontmatter)" short:"t"`
 	Prop     []string `help:"Property key=value (repeatable)" short:"P"`
-	Content  string   `help:"Inline markdown body" short:"c" xor:"body"`
-	File     string   `help:"Read body from markdown file" short:"f" type:"existingfile" xor:"body"`
+	Content  string   `help:"Inline markdown body (- reads stdin)" short:"c" xor:"body"`
+	File     string   `help:"Read body from markdown file (- reads stdin)" short:"f" type:"existingfile" xor:"body"`
+	Icon     string   `help:"Entry icon: emoji, :shortcode: or image URL" short:"i"`
+	Cover    string   `help:"Cover image URL"`
+	IfExists string   `help:"When an entry with the same title (or --match) exists: create, skip, update or error" name:"if-exists" enum:"create,skip,update,error" default:"create"`
+	Match    string   `help:"Identify an existing entry by Prop=Value instead of by title" short:"m"`
 	JSON     bool     `help:"Output as JSON" short:"j"`
 }
 
 func (c *DBCreateCmd) Run(ctx *Context) error {
 	ctx.JSON = c.JSON
-	return runDBCreate(ctx, c.Database, c.Title, c.Prop, c.Content, c.File)
+	return runDBCreate(ctx, c.Database, c.Title, c.Prop, c.Content, c.File, c.Icon, c.Cover, c.IfExists, c.Match)
 }
 
-func runDBCreate(ctx *Context, database, title string, props []string, content, file string) error {
+type DBArchiveCmd struct {
+	Entries []string `arg:"" optional:"" help:"Entry URLs, names, or IDs (reads a list from stdin if omitted or -)"`
+	Yes     bool     `help:"Skip confirmation prompt" short:"y"`
+	JSON    bool     `help:"Output as JSON" short:"j"`
+}
+
+func (c *DBArchiveCmd) Run(ctx *Context) error {
+	ctx.JSON = c.JSON
+	return runArchive(ctx, c.Entries, true, c.Yes, "entry")
+}
+
+type DBRestoreCmd struct {
+	Entries []string `arg:"" optional:"" help:"Entry URLs, names, or IDs (reads a list from stdin if omitted or -)"`
+	Yes     bool     `help:"Skip confirmation prompt" short:"y"`
+	JSON    bool     `help:"Output as JSON" short:"j"`
+}
+
+func (c *DBRestoreCmd) Run(ctx *Context) error {
+	ctx.JSON = c.JSON
+	return runArchive(ctx, c.Entries, false, c.Yes, "entry")
+}
+
+func runDBCreate(ctx *Context, database, title string, props []string, content, file, icon, cover, ifExists, match string) error {
+	var fm cli.Frontmatter
+	var err error
 	if file != "" {
-		data, err := os.ReadFile(file)
+		data, err := cli.ReadContentFile(file)
 		if err != nil {
 			output.PrintError(err)
 			return err
 		}
-		content = string(data)
+		fm, content = cli.ParseFrontmatter(data)
+		content = cli.MarkdownToNotion(content)
+	} else if content, err = cli.ReadContent(content); err != nil {
+		output.PrintError(err)
+		return err
+	}
+	if title == "" {
+		title = fm.Title
+	}
+	if title == "" {
+		err := &output.UserError{Message: "an entry title is required: use --title or set title in the file's frontmatter"}
+		output.PrintError(err)
+		return err
+	}
+	if icon == "" {
+		icon = fm.Icon
+	}
+	if cover == "" {
+		cover = fm.Cover
+	}
+	icon, cover, err = resolveIconCover(icon, cover)
+	if err != nil {
+		output.PrintError(err)
+		return err
 	}
 
 	client, err := cli.RequireClient()
@@ -123,14 +174,16 @@ func runDBCreate(ctx *Context, database, title string, props []string, content,
 		return err
 	}
 
-	properties := make(map[string]string)
-	for _, p := range props {
-		k, v, ok := strings.Cut(p, "=")
-		if !ok {
-			output.PrintError(&output.UserError{Message: "invalid property format (expected key=value): " + p})
-			return &output.UserError{Message: "invalid property format: " + p}
-		}
-		properties[k] = v
+	raw, err := cli.ParsePropertyArgs(props)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	properties, err := dbEntryProperties(bgCtx, client, dbID, file, fm, raw)
+	if err != nil {
+		output.PrintError(err)
+		return err
 	}
 
 	req := mcp.CreatePageRequest{
@@ -138,9 +191,11 @@ func runDBCreate(ctx *Context, database, title string, props []string, content,
 		Title:            title,
 		Content:          content,
 		Properties:       properties,
+		Icon:             icon,
+		Cover:            cover,
 	}
 
-	resp, err := client.CreatePage(bgCtx, req)
+	action, resp, err := createPageIfMissing(bgCtx, client, req, ifExists, match)
 	if err != nil {
 		output.PrintError(err)
 		return err
@@ -152,17 +207,60 @@ func runDBCreate(ctx *Context, database, title string, props []string, content,
 			URL:   resp.URL,
 			Title: title,
 		}
-		return output.PrintPage(outPage, true)
+		return output.PrintPageAction(output.PageAction{Page: outPage, Action: action})
 	}
 
+	message := "Entry " + action
+	if action == "skipped" {
+		message = "Entry already exists, skipped"
+	}
 	if resp.URL != "" {
-		output.PrintSuccess("Entry created: " + resp.URL)
+		message += ": " + resp.URL
+	}
+	if action == "skipped" {
+		output.PrintInfo(message)
 	} else {
-		output.PrintSuccess("Entry created")
+		output.PrintSuccess(message)
 	}
 	return nil
 }
 
+// dbEntryProperties types the frontmatter and --prop properties of a new entry
+// by the database schema. The schema is only read when there are properties.
+// If it can't be read, the values are sent as given; --prop keys the schema
+// doesn't have are always sent as given.
+func dbEntryProperties(ctx context.Context, client *mcp.Client, dataSourceID, file string, fm cli.Frontmatter, raw map[string]string) (map[string]any, error) {
+	properties := map[string]any{}
+	if len(raw) == 0 && len(fm.Properties) == 0 {
+		return properties, nil
+	}
+
+	schema, err := dataSourceSchema(ctx, client, dataSourceID)
+	if err != nil {
+		output.PrintWarning("Could not read the database schema, sending properties as given: " + err.Error())
+		for k, v := range fm.Properties {
+			properties[k] = v
+		}
+		for k, v := range raw {
+			properties[k] = v
+		}
+		return properties, nil
+	}
+
+	properties, err = frontmatterProperties(file, schema, fm, false)
+	if err != nil {
+		return nil, err
+	}
+	flagProps, err := cli.CoerceKnownProperties(schema, raw)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range flagProps {
+		properties[k] = v
+	}
+	return properties, nil
+}
+
 func runDBQuery(ctx *Context, id string) error {
 	client, err := cli.RequireClient()
 	if err != nil {
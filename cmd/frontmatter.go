@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+// resolveFrontmatterParent resolves the parent or parent-db named in a file's
+// frontmatter, returning either a page ID or a data source ID.
+func resolveFrontmatterParent(ctx context.Context, client *mcp.Client, fm cli.Frontmatter) (pageID, dataSourceID string, err error) {
+	switch {
+	case fm.ParentDB != "":
+		dbID, err := cli.ResolveDatabaseID(ctx, client, fm.ParentDB)
+		if err != nil {
+			return "", "", err
+		}
+		dataSourceID, err = client.ResolveDataSourceID(ctx, dbID)
+		return "", dataSourceID, err
+	case fm.Parent != "":
+		kind, id, err := cli.ResolveParentID(ctx, client, fm.Parent)
+		if err != nil {
+			return "", "", err
+		}
+		if kind == cli.ParentDatabase {
+			return "", id, nil
+		}
+		return id, "", nil
+	}
+	return "", "", nil
+}
+
+// resolveIconCover checks and normalises a page's icon and cover, turning
+// :shortcode: icons into emoji.
+func resolveIconCover(icon, cover string) (string, string, error) {
+	icon, err := cli.ResolveIcon(icon)
+	if err != nil {
+		return "", "", err
+	}
+	cover, err = cli.ResolveCover(cover)
+	if err != nil {
+		return "", "", err
+	}
+	return icon, cover, nil
+}
+
+// dataSourceSchema fetches the property schema of a data source, or returns
+// nil when there is no data source.
+func dataSourceSchema(ctx context.Context, client *mcp.Client, dataSourceID string) (map[string]mcp.PropertySchema, error) {
+	if dataSourceID == "" {
+		return nil, nil
+	}
+	return client.FetchSchema(ctx, "collection://"+dataSourceID)
+}
+
+// frontmatterProperties converts a file's frontmatter into properties for the
+// given schema, warning about keys that aren't properties. Set withTitle to
+// include the title property; page creation passes the title separately.
+func frontmatterProperties(file string, schema map[string]mcp.PropertySchema, fm cli.Frontmatter, withTitle bool) (map[string]any, error) {
+	if !withTitle {
+		fm.Title = ""
+	}
+	props, ignored, err := cli.FrontmatterProperties(schema, fm)
+	if err != nil {
+		return nil, err
+	}
+	if len(ignored) > 0 {
+		reason := "not properties of the database"
+		if schema == nil {
+			reason = "only database entries have properties"
+		}
+		output.PrintWarning(file + ": ignoring frontmatter " + strings.Join(ignored, ", ") + " (" + reason + ")")
+	}
+	return props, nil
+}
@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+// unresolvedLink is a link to a Markdown file or page name that has no page
+// yet.
+type unresolvedLink struct {
+	File   string
+	Target string
+	Path   string
+	Reason string
+}
+
+// pageLinker rewrites relative links between Markdown files into links to the
+// pages those files are synced to, and [[wiki-links]] into page mentions. It
+// is safe for concurrent use, so a directory sync can register pages as they
+// are created.
+type pageLinker struct {
+	client *mcp.Client
+	root   string // vault for wiki-links outside an Obsidian vault; "" for the file's directory
+
+	mu         sync.Mutex
+	ids        map[string]string            // absolute file path -> page ID
+	unresolved map[string][]unresolvedLink  // by linking file
+	vaults     map[string]cli.VaultIndex    // by vault root
+	fileNames  map[string]map[string]string // by vault root: page ID -> file name
+	names      map[string]string            // workspace page name -> page ID ("" if not found)
+	titles     map[string]string            // page ID -> title
+}
+
+func newPageLinker(client *mcp.Client, root string) *pageLinker {
+	return &pageLinker{
+		client:     client,
+		root:       root,
+		ids:        map[string]string{},
+		unresolved: map[string][]unresolvedLink{},
+		vaults:     map[string]cli.VaultIndex{},
+		fileNames:  map[string]map[string]string{},
+		names:      map[string]string{},
+		titles:     map[string]string{},
+	}
+}
+
+// register records the page a file is synced to.
+func (l *pageLinker) register(file, pageID string) {
+	if l == nil || pageID == "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := linkKey(file)
+	l.ids[key] = pageID
+	if id, ok := cli.ExtractNotionUUID(pageID); ok {
+		for root, names := range l.fileNames {
+			if strings.HasPrefix(key, linkKey(root)+string(filepath.Separator)) {
+				names[id] = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+			}
+		}
+	}
+}
+
+// pageID returns the page a file is synced to, from an earlier register or
+// the notion-id in the file's frontmatter. The reason explains a miss.
+func (l *pageLinker) pageID(path string) (id, reason string) {
+	key := linkKey(path)
+	l.mu.Lock()
+	id = l.ids[key]
+	l.mu.Unlock()
+	if id != "" {
+		return id, ""
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", "file not found"
+		}
+		return "", err.Error()
+	}
+	fm, _ := cli.ParseFrontmatter(string(data))
+	if fm.NotionID == "" {
+		return "", "not synced to Notion"
+	}
+	l.register(path, fm.NotionID)
+	return fm.NotionID, ""
+}
+
+// rewrite returns body, which belongs to file, with links to synced Markdown
+// files pointing at their pages and wiki-links turned into page mentions.
+// Links that can't be resolved are left as written and recorded for report.
+func (l *pageLinker) rewrite(ctx context.Context, file, body string) string {
+	if l == nil {
+		return body
+	}
+
+	var unresolved []unresolvedLink
+	rewritten := cli.RewriteWikiLinks(body, func(link cli.WikiLink) (string, bool) {
+		id, path, reason := l.wikiPageID(ctx, file, link.Target)
+		if id == "" {
+			unresolved = append(unresolved, unresolvedLink{File: file, Target: "[[" + link.Target + "]]", Path: path, Reason: reason})
+			return "", false
+		}
+		if link.Heading != "" || link.Alias != "" {
+			return cli.WikiLinkMarkdown(link, pageURL(id)), true
+		}
+		return `<mention-page url="` + pageURL(id) + `">` + link.Text() + `</mention-page>`, true
+	})
+	rewritten = cli.RewriteLocalLinks(rewritten, filepath.Dir(file), func(link cli.LocalLink) (string, bool) {
+		if !link.IsMarkdown() {
+			return "", false
+		}
+		id, reason := l.pageID(link.Path)
+		if id == "" {
+			unresolved = append(unresolved, unresolvedLink{File: file, Target: link.Target, Path: link.Path, Reason: reason})
+			return "", false
+		}
+		url := pageURL(id)
+		if link.Fragment != "" {
+			url += "#" + link.Fragment
+		}
+		return "[" + link.Text + "](" + url + ")", true
+	})
+
+	l.mu.Lock()
+	if len(unresolved) > 0 {
+		l.unresolved[file] = unresolved
+	} else {
+		delete(l.unresolved, file)
+	}
+	l.mu.Unlock()
+	return rewritten
+}
+
+// vault returns the wiki-link index for file's vault.
+func (l *pageLinker) vault(file string) (string, cli.VaultIndex) {
+	root := cli.VaultRoot(file)
+	if root == "" {
+		root = l.root
+	}
+	if root == "" {
+		root = filepath.Dir(file)
+	}
+
+	l.mu.Lock()
+	index, ok := l.vaults[root]
+	l.mu.Unlock()
+	if ok {
+		return root, index
+	}
+
+	index, err := cli.IndexVault(root)
+	if err != nil {
+		output.PrintWarning("Could not index " + root + " for wiki-links: " + err.Error())
+	}
+	l.mu.Lock()
+	l.vaults[root] = index
+	l.mu.Unlock()
+	return root, index
+}
+
+// wikiPageID resolves a wiki-link target, first to a file in the vault and
+// then to a page of that name in the workspace. path is the matching file,
+// if any, so the link can be retried once that file is synced.
+func (l *pageLinker) wikiPageID(ctx context.Context, file, target string) (id, path, reason string) {
+	if _, index := l.vault(file); index != nil {
+		if path, ok := index.Lookup(target); ok {
+			id, reason := l.pageID(path)
+			return id, path, reason
+		}
+	}
+
+	key := strings.ToLower(target)
+	l.mu.Lock()
+	id, looked := l.names[key]
+	l.mu.Unlock()
+	if !looked && l.client != nil {
+		id, _ = cli.ResolvePageID(ctx, l.client, target)
+		l.mu.Lock()
+		l.names[key] = id
+		l.mu.Unlock()
+	}
+	if id == "" {
+		return "", "", "no file or page with this name"
+	}
+	return id, "", ""
+}
+
+// wikiName returns a function for cli.MentionsToWikiLinks that names
+// mentioned pages after their files in file's vault, falling back to the
+// page title.
+func (l *pageLinker) wikiName(ctx context.Context, file string) func(id, title string) string {
+	fileName := l.vaultName(file)
+	return func(id, title string) string {
+		if name, ok := fileName(id); ok {
+			return name
+		}
+		if title != "" {
+			return title
+		}
+		return l.pageTitle(ctx, id)
+	}
+}
+
+// vaultName returns a function that names a page after its file in file's
+// vault. The vault's files are read once per run.
+func (l *pageLinker) vaultName(file string) func(id string) (string, bool) {
+	if file == "" {
+		return func(string) (string, bool) { return "", false }
+	}
+	root, index := l.vault(file)
+
+	l.mu.Lock()
+	names, ok := l.fileNames[root]
+	l.mu.Unlock()
+	if !ok {
+		names = map[string]string{}
+		for _, path := range index {
+			pageID, _ := l.pageID(path)
+			if id, ok := cli.ExtractNotionUUID(pageID); ok {
+				names[id] = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			}
+		}
+		l.mu.Lock()
+		if cached, ok := l.fileNames[root]; ok {
+			names = cached
+		} else {
+			l.fileNames[root] = names
+		}
+		l.mu.Unlock()
+	}
+
+	return func(id string) (string, bool) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		name, ok := names[id]
+		return name, ok
+	}
+}
+
+// pageTitle fetches the title of a page, for mentions that don't carry one.
+func (l *pageLinker) pageTitle(ctx context.Context, id string) string {
+	l.mu.Lock()
+	title, ok := l.titles[id]
+	l.mu.Unlock()
+	if ok || l.client == nil {
+		return title
+	}
+	if page, err := l.client.Fetch(ctx, id); err == nil {
+		title = page.Title
+	}
+	l.mu.Lock()
+	l.titles[id] = title
+	l.mu.Unlock()
+	return title
+}
+
+// pending returns the synced files whose unresolved links now point at
+// registered pages, so they can be pushed again.
+func (l *pageLinker) pending() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var files []string
+	for file, links := range l.unresolved {
+		if l.ids[linkKey(file)] == "" {
+			continue
+		}
+		for _, link := range links {
+			if l.ids[linkKey(link.Path)] != "" {
+				files = append(files, file)
+				break
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// report prints a warning listing links that were left unresolved.
+func (l *pageLinker) report() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var links []unresolvedLink
+	for _, fileLinks := range l.unresolved {
+		links = append(links, fileLinks...)
+	}
+	if len(links) == 0 {
+		return
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].File != links[j].File {
+			return links[i].File < links[j].File
+		}
+		return links[i].Target < links[j].Target
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d link(s) without a page were left unchanged:", len(links))
+	for _, link := range links {
+		fmt.Fprintf(&b, "\n  %s -> %s (%s)", link.File, link.Target, link.Reason)
+	}
+	output.PrintWarning(b.String())
+}
+
+func linkKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// pageURL returns the notion.so URL for a page ID.
+func pageURL(id string) string {
+	return "https://www.notion.so/" + strings.ReplaceAll(id, "-", "")
+}
@@ -13,12 +13,22 @@ import (
 )
 
 type PageCmd struct {
-	List   PageListCmd   `cmd:"" help:"List pages"`
-	View   PageViewCmd   `cmd:"" help:"View a page"`
-	Create PageCreateCmd `cmd:"" help:"Create a page"`
-	Upload PageUploadCmd `cmd:"" help:"Upload a markdown file as a page"`
-	Sync   PageSyncCmd   `cmd:"" help:"Sync a markdown file to a page (create or update)"`
-	Edit   PageEditCmd   `cmd:"" help:"Edit a page"`
+	List      PageListCmd      `cmd:"" help:"List pages"`
+	View      PageViewCmd      `cmd:"" help:"View a page"`
+	Tree      PageTreeCmd      `cmd:"" help:"Show a page's child pages and databases as a tree"`
+	Create    PageCreateCmd    `cmd:"" help:"Create a page"`
+	Upload    PageUploadCmd    `cmd:"" help:"Upload a markdown file as a page"`
+	Sync      PageSyncCmd      `cmd:"" help:"Sync a markdown file to a page (create or update)"`
+	Diff      PageDiffCmd      `cmd:"" help:"Show differences between a markdown file and a page"`
+	Watch     PageWatchCmd     `cmd:"" help:"Watch a page and print its changes"`
+	Edit      PageEditCmd      `cmd:"" help:"Edit a page"`
+	Append    PageAppendCmd    `cmd:"" help:"Add markdown to the end of a page"`
+	Prepend   PagePrependCmd   `cmd:"" help:"Add markdown to the start of a page"`
+	Set       PageSetCmd       `cmd:"" help:"Set page properties"`
+	Move      PageMoveCmd      `cmd:"" help:"Move pages to a new parent"`
+	Duplicate PageDuplicateCmd `cmd:"" help:"Duplicate a page"`
+	Archive   PageArchiveCmd   `cmd:"" help:"Archive (trash) pages"`
+	Restore   PageRestoreCmd   `cmd:"" help:"Restore archived pages"`
 }
 
 type PageListCmd struct {
@@ -66,26 +76,30 @@ func filterPages(results []mcp.SearchResult, limit int) []output.Page {
 			break
 		}
 		pages = append(pages, output.Page{
-			ID:    r.ID,
-			Title: r.Title,
-			URL:   r.URL,
+			ID:       r.ID,
+			Title:    r.Title,
+			URL:      r.URL,
+			Archived: r.Archived,
 		})
 	}
 	return pages
 }
 
 type PageViewCmd struct {
-	Page string `arg:"" help:"Page URL, name, or ID"`
-	JSON bool   `help:"Output as JSON" short:"j"`
-	Raw  bool   `help:"Output raw Notion response without formatting" short:"r"`
+	Page      string `arg:"" help:"Page URL, name, or ID"`
+	JSON      bool   `help:"Output as JSON" short:"j"`
+	Raw       bool   `help:"Output raw Notion response without formatting" short:"r" xor:"format"`
+	Markdown  bool   `help:"Output plain Markdown, e.g. to export the page" short:"m" xor:"format"`
+	WikiLinks bool   `help:"Write page mentions as [[wiki-links]] (with --markdown)" name:"wiki-links"`
+	Section   string `help:"Only show the section under this heading" short:"s"`
 }
 
 func (c *PageViewCmd) Run(ctx *Context) error {
 	ctx.JSON = c.JSON
-	return runPageView(ctx, c.Page, c.Raw)
+	return runPageView(ctx, c.Page, c.Raw, c.Markdown, c.WikiLinks, c.Section)
 }
 
-func runPageView(ctx *Context, page string, raw bool) error {
+func runPageView(ctx *Context, page string, raw, markdown, wikiLinks bool, section string) error {
 	client, err := cli.RequireClient()
 	if err != nil {
 		return err
@@ -111,6 +125,31 @@ func runPageView(ctx *Context, page string, raw bool) error {
 		return err
 	}
 
+	if section != "" {
+		body := result.Body()
+		s, err := cli.FindSection(body, section)
+		if err != nil {
+			err = &output.UserError{Message: err.Error()}
+			output.PrintError(err)
+			return err
+		}
+		result.Content = body[s.Start:s.End]
+	}
+
+	if ctx.JSON {
+		pageID, _ := cli.ExtractNotionUUID(fetchID)
+		outPage := output.Page{
+			ID:         pageID,
+			Title:      result.Title,
+			URL:        result.URL,
+			ParentType: result.ParentType,
+			ParentID:   result.ParentID,
+			Archived:   result.Archived,
+			Content:    result.Content,
+		}
+		return output.PrintPage(outPage, true)
+	}
+
 	if result.Content == "" {
 		output.PrintWarning("No content found")
 		return nil
@@ -121,22 +160,80 @@ func runPageView(ctx *Context, page string, raw bool) error {
 		return nil
 	}
 
+	if markdown {
+		content := result.Body()
+		if section != "" {
+			content = result.Content
+		}
+		if wikiLinks {
+			content = cli.MentionsToWikiLinks(content, newPageLinker(client, "").wikiName(bgCtx, ""))
+		}
+		fmt.Println(output.NotionToMarkdown(content))
+		return nil
+	}
+
+	if result.Archived {
+		output.PrintWarning("This page is archived")
+	}
+
+	if section != "" {
+		return output.RenderMarkdown(result.Content)
+	}
 	return output.RenderPage(result.Content)
 }
 
 type PageCreateCmd struct {
-	Title   string `help:"Page title" short:"t" required:""`
-	Parent  string `help:"Parent page URL, name, or ID" short:"p"`
-	Content string `help:"Page content (markdown)" short:"c"`
-	JSON    bool   `help:"Output as JSON" short:"j"`
+	Title    string   `help:"Page title (default: the template's title)" short:"t"`
+	Parent   string   `help:"Parent page URL, name, or ID" short:"p"`
+	ParentDB string   `help:"Parent database URL, name, or ID" name:"parent-db" short:"d"`
+	Content  string   `help:"Page content (markdown, - reads stdin)" short:"c" xor:"body"`
+	File     string   `help:"Read page content from a markdown file (- reads stdin)" short:"f" type:"existingfile" xor:"body"`
+	Template string   `help:"Template page (URL, name, or ID) or markdown file to create the page from" short:"T" xor:"body"`
+	Var      []string `help:"Template variable key=value (repeatable)" short:"V" sep:"none"`
+	Icon     string   `help:"Page icon: emoji, :shortcode: or image URL" short:"i"`
+	Cover    string   `help:"Cover image URL"`
+	Batch    string   `help:"Create the pages listed in a JSONL file, one per line (- reads stdin)" short:"b" type:"existingfile" xor:"body"`
+	JSON     bool     `help:"Output as JSON" short:"j"`
 }
 
 func (c *PageCreateCmd) Run(ctx *Context) error {
 	ctx.JSON = c.JSON
-	return runPageCreate(ctx, c.Title, c.Parent, c.Content)
+	if c.Batch != "" {
+		if c.Title != "" || len(c.Var) > 0 || c.Icon != "" || c.Cover != "" {
+			return &output.UserError{Message: "--batch takes titles, icons and covers from the file; only --parent and --parent-db can be combined with it"}
+		}
+		return runPageBatch(ctx, c.Batch, c.Parent, c.ParentDB)
+	}
+	if len(c.Var) > 0 && c.Template == "" {
+		return &output.UserError{Message: "--var requires --template"}
+	}
+	return runPageCreate(ctx, c.Title, c.Parent, c.ParentDB, c.Content, c.File, c.Template, c.Var, c.Icon, c.Cover)
 }
 
-func runPageCreate(ctx *Context, title, parent, content string) error {
+func runPageCreate(ctx *Context, title, parent, parentDB, content, file, template string, vars []string, icon, cover string) error {
+	var fm cli.Frontmatter
+	var err error
+	if file != "" {
+		data, err := cli.ReadContentFile(file)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		fm, content = cli.ParseFrontmatter(data)
+		content = cli.MarkdownToNotion(content)
+	} else if content, err = cli.ReadContent(content); err != nil {
+		output.PrintError(err)
+		return err
+	}
+	if title == "" {
+		title = fm.Title
+	}
+	if title == "" && template == "" {
+		err := &output.UserError{Message: "a page title is required: use --title or set title in the file's frontmatter"}
+		output.PrintError(err)
+		return err
+	}
+
 	client, err := cli.RequireClient()
 	if err != nil {
 		return err
@@ -145,23 +242,85 @@ func runPageCreate(ctx *Context, title, parent, content string) error {
 
 	bgCtx := context.Background()
 
-	parentID := parent
-	if parent != "" {
-		resolved, err := cli.ResolvePageID(bgCtx, client, parent)
+	if template != "" {
+		fm, content, err = expandPageTemplate(bgCtx, client, template, vars)
 		if err != nil {
 			output.PrintError(err)
 			return err
 		}
-		parentID = resolved
+		if title == "" {
+			title = fm.Title
+		}
+		if title == "" {
+			err := &output.UserError{Message: "a page title is required: use --title or set title in the template"}
+			output.PrintError(err)
+			return err
+		}
+	}
+
+	if icon == "" {
+		icon = fm.Icon
+	}
+	if cover == "" {
+		cover = fm.Cover
+	}
+	icon, cover, err = resolveIconCover(icon, cover)
+	if err != nil {
+		output.PrintError(err)
+		return err
 	}
 
 	req := mcp.CreatePageRequest{
-		Title:        title,
-		ParentPageID: parentID,
-		Content:      content,
+		Title:   title,
+		Content: content,
+		Icon:    icon,
+		Cover:   cover,
+	}
+
+	switch {
+	case parentDB != "":
+		dbID, err := cli.ResolveDatabaseID(bgCtx, client, parentDB)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		req.ParentDatabaseID, err = client.ResolveDataSourceID(bgCtx, dbID)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+	case parent != "":
+		req.ParentPageID, err = cli.ResolvePageID(bgCtx, client, parent)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+	default:
+		req.ParentPageID, req.ParentDatabaseID, err = resolveFrontmatterParent(bgCtx, client, fm)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+	}
+
+	if len(fm.Properties) > 0 {
+		schema, err := dataSourceSchema(bgCtx, client, req.ParentDatabaseID)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		source := file
+		if template != "" {
+			source = template
+		}
+		req.Properties, err = frontmatterProperties(source, schema, fm, false)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
 	}
 
-	resp, err := client.CreatePage(bgCtx, req)
+	resp, err := cli.CreatePage(bgCtx, client, req)
 	if err != nil {
 		output.PrintError(err)
 		return err
@@ -172,6 +331,7 @@ func runPageCreate(ctx *Context, title, parent, content string) error {
 			ID:    resp.ID,
 			URL:   resp.URL,
 			Title: title,
+			Icon:  icon,
 		}
 		return output.PrintPage(outPage, true)
 	}
@@ -189,24 +349,30 @@ type PageUploadCmd struct {
 	Title    string `help:"Page title (default: filename or first heading)" short:"t"`
 	Parent   string `help:"Parent page URL, name, or ID" short:"p"`
 	ParentDB string `help:"Parent database URL, name, or ID" name:"parent-db" short:"d"`
-	Icon     string `help:"Emoji icon for the page" short:"i"`
+	Icon     string `help:"Page icon: emoji, :shortcode: or image URL" short:"i"`
+	Cover    string `help:"Cover image URL"`
+	IfExists string `help:"When a page with the same title (or --match) exists under the parent: create, skip, update or error" name:"if-exists" enum:"create,skip,update,error" default:"create"`
+	Match    string `help:"Identify an existing database entry by Prop=Value instead of by title" short:"m"`
 	JSON     bool   `help:"Output as JSON" short:"j"`
 }
 
 func (c *PageUploadCmd) Run(ctx *Context) error {
 	ctx.JSON = c.JSON
-	return runPageUpload(ctx, c.File, c.Title, c.Parent, c.ParentDB, c.Icon)
+	return runPageUpload(ctx, c.File, c.Title, c.Parent, c.ParentDB, c.Icon, c.Cover, c.IfExists, c.Match)
 }
 
-func runPageUpload(ctx *Context, file, title, parent, parentDB, icon string) error {
+func runPageUpload(ctx *Context, file, title, parent, parentDB, icon, cover, ifExists, match string) error {
 	content, err := os.ReadFile(file)
 	if err != nil {
 		output.PrintError(err)
 		return err
 	}
 
-	markdown := string(content)
+	fm, markdown := cli.ParseFrontmatter(string(content))
 
+	if title == "" {
+		title = fm.Title
+	}
 	if title == "" {
 		title = extractTitleFromMarkdown(markdown)
 	}
@@ -214,9 +380,20 @@ func runPageUpload(ctx *Context, file, title, parent, parentDB, icon string) err
 		title = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
 	}
 
+	if icon == "" {
+		icon = fm.Icon
+	}
 	if icon == "" {
 		icon, title = extractEmojiFromTitle(title)
 	}
+	if cover == "" {
+		cover = fm.Cover
+	}
+	icon, cover, err = resolveIconCover(icon, cover)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
 
 	client, err := cli.RequireClient()
 	if err != nil {
@@ -226,9 +403,18 @@ func runPageUpload(ctx *Context, file, title, parent, parentDB, icon string) err
 
 	bgCtx := context.Background()
 
+	links := newPageLinker(client, "")
+	markdown = links.rewrite(bgCtx, file, cli.MarkdownToNotion(markdown))
+	assets := newAssetChecker()
+	assets.check(file, markdown)
+	assets.report()
+	links.report()
+
 	req := mcp.CreatePageRequest{
 		Title:   title,
 		Content: markdown,
+		Icon:    icon,
+		Cover:   cover,
 	}
 
 	if parentDB != "" {
@@ -250,9 +436,28 @@ func runPageUpload(ctx *Context, file, title, parent, parentDB, icon string) err
 			return err
 		}
 		req.ParentPageID = parentID
+	} else {
+		req.ParentPageID, req.ParentDatabaseID, err = resolveFrontmatterParent(bgCtx, client, fm)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
 	}
 
-	resp, err := client.CreatePage(bgCtx, req)
+	if len(fm.Properties) > 0 {
+		schema, err := dataSourceSchema(bgCtx, client, req.ParentDatabaseID)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		req.Properties, err = frontmatterProperties(file, schema, fm, false)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+	}
+
+	action, resp, err := createPageIfMissing(bgCtx, client, req, ifExists, match)
 	if err != nil {
 		output.PrintError(err)
 		return err
@@ -270,10 +475,17 @@ func runPageUpload(ctx *Context, file, title, parent, parentDB, icon string) err
 			Title: displayTitle,
 			Icon:  icon,
 		}
-		return output.PrintPage(outPage, true)
+		return output.PrintPageAction(output.PageAction{Page: outPage, Action: action})
 	}
 
-	output.PrintSuccess("Uploaded: " + displayTitle)
+	switch action {
+	case "skipped":
+		output.PrintInfo("Skipped: " + displayTitle + " already exists")
+	case "updated":
+		output.PrintSuccess("Updated: " + displayTitle)
+	default:
+		output.PrintSuccess("Uploaded: " + displayTitle)
+	}
 	if resp.URL != "" {
 		output.PrintInfo(resp.URL)
 	}
@@ -308,17 +520,49 @@ func extractEmojiFromTitle(title string) (icon, cleanTitle string) {
 
 type PageEditCmd struct {
 	Page        string `arg:"" help:"Page URL, name, or ID"`
-	Replace     string `help:"Replace entire content with this text" xor:"action"`
+	Replace     string `help:"Replace entire content with this text (- reads stdin)" xor:"action"`
+	File        string `help:"Replace entire content with a markdown file (- reads stdin)" short:"f" type:"existingfile" xor:"action"`
 	Find        string `help:"Text to find (use ... for ellipsis)" xor:"action"`
-	ReplaceWith string `help:"Text to replace with (requires --find)" name:"replace-with"`
-	Append      string `help:"Append text after selection (requires --find)" xor:"action"`
+	ReplaceWith string `help:"Text to replace with (requires --find, - reads stdin)" name:"replace-with"`
+	Append      string `help:"Append text after selection (requires --find, - reads stdin)" xor:"action"`
+	Editor      bool   `help:"Edit the page content in $EDITOR and apply the changes" short:"e" xor:"action"`
+
+	Section        string `help:"Heading of the section to edit (with --replace-section)" short:"s"`
+	ReplaceSection string `help:"Replace the section's content, keeping its heading (- reads stdin)" name:"replace-section" xor:"action"`
 }
 
 func (c *PageEditCmd) Run(ctx *Context) error {
-	return runPageEdit(ctx, c.Page, c.Replace, c.Find, c.ReplaceWith, c.Append)
+	if (c.Section == "") != (c.ReplaceSection == "") {
+		return &output.UserError{Message: "--section and --replace-section must be used together"}
+	}
+	if c.Section != "" {
+		return runPageEditSection(ctx, c.Page, c.Section, c.ReplaceSection)
+	}
+	if c.File != "" {
+		content, err := cli.ReadContentFile(c.File)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		_, body := cli.ParseFrontmatter(content)
+		c.Replace = cli.MarkdownToNotion(body)
+	}
+	return runPageEdit(ctx, c.Page, c.Replace, c.Find, c.ReplaceWith, c.Append, c.Editor)
 }
 
-func runPageEdit(ctx *Context, page, replace, find, replaceWith, appendText string) error {
+func runPageEdit(ctx *Context, page, replace, find, replaceWith, appendText string, editor bool) error {
+	if err := cli.StdinOnce(replace, replaceWith, appendText); err != nil {
+		output.PrintError(err)
+		return err
+	}
+	var err error
+	for _, text := range []*string{&replace, &replaceWith, &appendText} {
+		if *text, err = cli.ReadContent(*text); err != nil {
+			output.PrintError(err)
+			return err
+		}
+	}
+
 	client, err := cli.RequireClient()
 	if err != nil {
 		return err
@@ -341,13 +585,23 @@ func runPageEdit(ctx *Context, page, replace, find, replaceWith, appendText stri
 		pageID = ref.ID
 	}
 
+	if editor {
+		return runPageEditInEditor(bgCtx, client, pageID)
+	}
+
+	if replace != "" {
+		if err := cli.ReplaceContent(bgCtx, client, pageID, replace); err != nil {
+			output.PrintError(err)
+			return err
+		}
+		output.PrintSuccess("Page updated")
+		return nil
+	}
+
 	var req mcp.UpdatePageRequest
 	req.PageID = pageID
 
 	switch {
-	case replace != "":
-		req.Command = "replace_content"
-		req.NewContent = replace
 	case find != "" && replaceWith != "":
 		req.Command = "replace_content_range"
 		req.Selection = find
@@ -357,7 +611,7 @@ func runPageEdit(ctx *Context, page, replace, find, replaceWith, appendText stri
 		req.Selection = find
 		req.NewStr = appendText
 	default:
-		return &output.UserError{Message: "specify --replace, or --find with --replace-with or --append"}
+		return &output.UserError{Message: "specify --replace, --editor, or --find with --replace-with or --append"}
 	}
 
 	if err := client.UpdatePage(bgCtx, req); err != nil {
@@ -368,146 +622,3 @@ func runPageEdit(ctx *Context, page, replace, find, replaceWith, appendText stri
 	output.PrintSuccess("Page updated")
 	return nil
 }
-
-type PageSyncCmd struct {
-	File     string `arg:"" help:"Markdown file to sync" type:"existingfile"`
-	Title    string `help:"Page title (default: filename or first heading)" short:"t"`
-	Parent   string `help:"Parent page URL, name, or ID" short:"p"`
-	ParentDB string `help:"Parent database URL, name, or ID" name:"parent-db" short:"d"`
-	Icon     string `help:"Emoji icon for the page" short:"i"`
-	JSON     bool   `help:"Output as JSON" short:"j"`
-}
-
-func (c *PageSyncCmd) Run(ctx *Context) error {
-	ctx.JSON = c.JSON
-	return runPageSync(ctx, c.File, c.Title, c.Parent, c.ParentDB, c.Icon)
-}
-
-func runPageSync(ctx *Context, file, title, parent, parentDB, icon string) error {
-	raw, err := os.ReadFile(file)
-	if err != nil {
-		output.PrintError(err)
-		return err
-	}
-
-	content := string(raw)
-	fm, body := cli.ParseFrontmatter(content)
-
-	if title == "" {
-		title = extractTitleFromMarkdown(body)
-	}
-	if title == "" {
-		title = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
-	}
-	if icon == "" {
-		icon, title = extractEmojiFromTitle(title)
-	}
-
-	client, err := cli.RequireClient()
-	if err != nil {
-		return err
-	}
-	defer func() { _ = client.Close() }()
-
-	bgCtx := context.Background()
-
-	if fm.NotionID != "" {
-		req := mcp.UpdatePageRequest{
-			PageID:     fm.NotionID,
-			Command:    "replace_content",
-			NewContent: body,
-		}
-		if err := client.UpdatePage(bgCtx, req); err != nil {
-			output.PrintError(err)
-			return err
-		}
-
-		displayTitle := title
-		if icon != "" {
-			displayTitle = icon + " " + title
-		}
-
-		if ctx.JSON {
-			outPage := output.Page{
-				ID:    fm.NotionID,
-				Title: displayTitle,
-				Icon:  icon,
-			}
-			return output.PrintPage(outPage, true)
-		}
-
-		output.PrintSuccess("Synced: " + displayTitle)
-		return nil
-	}
-
-	req := mcp.CreatePageRequest{
-		Title:   title,
-		Content: body,
-	}
-
-	if parentDB != "" {
-		dbID, err := cli.ResolveDatabaseID(bgCtx, client, parentDB)
-		if err != nil {
-			output.PrintError(err)
-			return err
-		}
-		dbID, err = client.ResolveDataSourceID(bgCtx, dbID)
-		if err != nil {
-			output.PrintError(err)
-			return err
-		}
-		req.ParentDatabaseID = dbID
-	} else if parent != "" {
-		parentID, err := cli.ResolvePageID(bgCtx, client, parent)
-		if err != nil {
-			output.PrintError(err)
-			return err
-		}
-		req.ParentPageID = parentID
-	}
-
-	resp, err := client.CreatePage(bgCtx, req)
-	if err != nil {
-		output.PrintError(err)
-		return err
-	}
-
-	pageID := resp.ID
-	if pageID == "" && resp.URL != "" {
-		pageID, _ = cli.ExtractNotionUUID(resp.URL)
-	}
-	if pageID == "" {
-		output.PrintWarning("Page created but could not retrieve ID for frontmatter")
-	} else {
-		updated := cli.SetFrontmatterID(content, pageID)
-		fileMode := os.FileMode(0o644)
-		if info, err := os.Stat(file); err == nil {
-			fileMode = info.Mode()
-		}
-		if err := os.WriteFile(file, []byte(updated), fileMode); err != nil {
-			output.PrintError(fmt.Errorf("page created but failed to update frontmatter: %w", err))
-			return err
-		}
-	}
-
-	displayTitle := title
-	if icon != "" {
-		displayTitle = icon + " " + title
-	}
-
-	if ctx.JSON {
-		outPage := output.Page{
-			ID:    pageID,
-			URL:   resp.URL,
-			Title: displayTitle,
-			Icon:  icon,
-		}
-		return output.PrintPage(outPage, true)
-	}
-
-	output.PrintSuccess("Created: " + displayTitle)
-	if resp.URL != "" {
-		output.PrintInfo(resp.URL)
-	}
-	return nil
-}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+type PageAppendCmd struct {
+	Page    string `arg:"" help:"Page URL, name, or ID"`
+	Content string `arg:"" optional:"" help:"Markdown to add (reads stdin if omitted or -)"`
+	File    string `help:"Read the markdown to add from a file (- reads stdin)" short:"f" type:"existingfile"`
+}
+
+func (c *PageAppendCmd) Run(ctx *Context) error {
+	return runPageInsert(ctx, c.Page, c.Content, c.File, false)
+}
+
+type PagePrependCmd struct {
+	Page    string `arg:"" help:"Page URL, name, or ID"`
+	Content string `arg:"" optional:"" help:"Markdown to add (reads stdin if omitted or -)"`
+	File    string `help:"Read the markdown to add from a file (- reads stdin)" short:"f" type:"existingfile"`
+}
+
+func (c *PagePrependCmd) Run(ctx *Context) error {
+	return runPageInsert(ctx, c.Page, c.Content, c.File, true)
+}
+
+// runPageInsert adds content at the end of a page, or at the start when
+// prepend is set. The anchor for the edit is worked out from the fetched
+// content; an empty page simply gets content as its body.
+func runPageInsert(ctx *Context, page, content, file string, prepend bool) error {
+	if content != "" && file != "" {
+		err := &output.UserError{Message: "give the content as an argument or with --file, not both"}
+		output.PrintError(err)
+		return err
+	}
+
+	var err error
+	switch {
+	case file != "":
+		content, err = cli.ReadContentFile(file)
+	case content == "":
+		content, err = cli.ReadContent("-")
+	default:
+		content, err = cli.ReadContent(content)
+	}
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+	if content == "" {
+		err := &output.UserError{Message: "nothing to add: content is empty"}
+		output.PrintError(err)
+		return err
+	}
+
+	client, err := cli.RequireClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	bgCtx := context.Background()
+
+	pageID, err := cli.ResolvePageID(bgCtx, client, page)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	fetched, err := client.Fetch(bgCtx, pageID)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+	body := fetched.Body()
+
+	req := mcp.UpdatePageRequest{PageID: pageID}
+	var edit cli.ContentEdit
+	var ok bool
+	if prepend {
+		edit, ok = cli.PlanPrepend(body, content)
+		req.Command = "replace_content_range"
+	} else {
+		edit, ok = cli.PlanAppend(body, content)
+		req.Command = "insert_content_after"
+	}
+	if ok {
+		req.Selection = edit.Selection
+		req.NewStr = edit.NewStr
+	} else if strings.TrimSpace(body) == "" {
+		req.Command = "replace_content"
+		req.NewContent = content
+	} else {
+		// Rewriting the whole page would drop anything the markdown can't
+		// represent, so refuse rather than fall back to replace_content.
+		err := &output.UserError{Message: "could not find a unique place in the page to anchor the edit; use 'notion-cli page edit' instead"}
+		output.PrintError(err)
+		return err
+	}
+
+	if err := client.UpdatePage(bgCtx, req); err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	if prepend {
+		output.PrintSuccess("Prepended to page")
+	} else {
+		output.PrintSuccess("Appended to page")
+	}
+	return nil
+}
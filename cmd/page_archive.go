@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+type PageArchiveCmd struct {
+	Pages []string `arg:"" optional:"" help:"Page URLs, names, or IDs (reads a list from stdin if omitted or -)"`
+	Yes   bool     `help:"Skip confirmation prompt" short:"y"`
+	JSON  bool     `help:"Output as JSON" short:"j"`
+}
+
+func (c *PageArchiveCmd) Run(ctx *Context) error {
+	ctx.JSON = c.JSON
+	return runArchive(ctx, c.Pages, true, c.Yes, "page")
+}
+
+type PageRestoreCmd struct {
+	Pages []string `arg:"" optional:"" help:"Page URLs, names, or IDs (reads a list from stdin if omitted or -)"`
+	Yes   bool     `help:"Skip confirmation prompt" short:"y"`
+	JSON  bool     `help:"Output as JSON" short:"j"`
+}
+
+func (c *PageRestoreCmd) Run(ctx *Context) error {
+	ctx.JSON = c.JSON
+	return runArchive(ctx, c.Pages, false, c.Yes, "page")
+}
+
+// runArchive archives or restores pages. Database entries are pages too, so
+// the db commands share this with noun set to "entry". Every page is looked
+// up before anything changes, so the prompt can name them; after that a
+// failure is reported and the rest are still done.
+func runArchive(ctx *Context, args []string, archive, yes bool, noun string) error {
+	refs, err := cli.RefsFromArgs(args)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+	if len(refs) == 0 {
+		return &output.UserError{Message: "no " + output.Plural(noun, 0) + " given"}
+	}
+
+	verb, done := "Archive", "archived"
+	if !archive {
+		verb, done = "Restore", "restored"
+	}
+
+	client, err := cli.RequireClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	bgCtx := context.Background()
+
+	results := make([]output.ArchiveResult, 0, len(refs))
+	for _, ref := range refs {
+		pageID, err := cli.ResolvePageID(bgCtx, client, ref)
+		if err != nil {
+			output.PrintError(fmt.Errorf("%s: %w", ref, err))
+			return err
+		}
+		page, err := client.Fetch(bgCtx, pageID)
+		if err != nil {
+			output.PrintError(fmt.Errorf("%s: %w", ref, err))
+			return err
+		}
+		results = append(results, output.ArchiveResult{ID: pageID, Title: page.Title, URL: page.URL})
+	}
+
+	if !yes && !cli.DryRun() {
+		var prompt strings.Builder
+		for _, r := range results {
+			fmt.Fprintf(&prompt, "  %s\n", r.Title)
+		}
+		fmt.Fprintf(&prompt, "%s %d %s?", verb, len(results), output.Plural(noun, len(results)))
+		ok, err := cli.Confirm(prompt.String())
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		if !ok {
+			return &output.UserError{Message: "aborted"}
+		}
+	}
+
+	failed := 0
+	for i := range results {
+		r := &results[i]
+		if err := cli.SetArchived(bgCtx, client, r.ID, archive); err != nil {
+			failed++
+			r.Action, r.Error = "failed", err.Error()
+			if !ctx.JSON {
+				output.PrintError(fmt.Errorf("%s: %w", r.Title, err))
+			}
+			continue
+		}
+		r.Action = done
+		if !ctx.JSON {
+			output.PrintSuccess(verb + "d: " + r.Title)
+		}
+	}
+
+	if ctx.JSON {
+		if err := output.PrintArchiveResults(results); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		output.PrintProgress(fmt.Sprintf("%sd %d of %d %s", verb, len(results)-failed, len(results), output.Plural(noun, len(results))))
+		return &output.ExitError{Code: 1}
+	}
+	return nil
+}
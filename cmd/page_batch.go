@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+// maxBatchPages is the most pages sent in one notion-create-pages call.
+const maxBatchPages = 100
+
+// batchResult reports what happened to one line of a batch. Input holds the
+// line as given, so failed items can be selected and run again.
+type batchResult struct {
+	Line   int             `json:"line"`
+	Title  string          `json:"title,omitempty"`
+	Status string          `json:"status"`
+	ID     string          `json:"id,omitempty"`
+	URL    string          `json:"url,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	Input  json.RawMessage `json:"input,omitempty"`
+}
+
+// runPageBatch creates the pages listed in a JSONL file, grouping pages with
+// the same parent into as few calls as possible. It prints one JSON result
+// per input line and exits with status 1 if any page failed.
+func runPageBatch(ctx *Context, batch, parent, parentDB string) error {
+	var r io.Reader = os.Stdin
+	if batch != "-" {
+		f, err := os.Open(batch)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+	items, err := cli.ReadBatchItems(r)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+	if len(items) == 0 {
+		return &output.UserError{Message: "the batch has no pages"}
+	}
+
+	client, err := cli.RequireClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	bgCtx := context.Background()
+	b := &pageBatch{client: client, parents: map[string]mcp.CreatePageRequest{}, schemas: map[string]map[string]mcp.PropertySchema{}}
+
+	results := make([]batchResult, len(items))
+	reqs := make([]mcp.CreatePageRequest, len(items))
+	var groupOrder []string
+	groups := map[string][]int{}
+	for i, item := range items {
+		results[i] = batchResult{Line: item.Line, Title: item.Title, Input: item.Raw}
+		if item.Err == nil {
+			item.Err = b.request(bgCtx, item, parent, parentDB, &reqs[i])
+		}
+		if item.Err != nil {
+			results[i].Status = "failed"
+			results[i].Error = item.Err.Error()
+			continue
+		}
+
+		key := reqs[i].ParentPageID + "|" + reqs[i].ParentDatabaseID
+		if _, ok := groups[key]; !ok {
+			groupOrder = append(groupOrder, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	for _, key := range groupOrder {
+		indexes := groups[key]
+		sizes := make([]int, len(indexes))
+		for j, i := range indexes {
+			sizes[j] = len(reqs[i].Title) + len(reqs[i].Content)
+		}
+		for _, group := range cli.GroupBatch(sizes, maxBatchPages, cli.MaxChunkSize) {
+			batchIndexes := make([]int, len(group))
+			for j, g := range group {
+				batchIndexes[j] = indexes[g]
+			}
+			b.create(bgCtx, reqs, batchIndexes, results)
+		}
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Status != "created" {
+			failed++
+		}
+		data, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	}
+
+	output.PrintProgress(fmt.Sprintf("Created %d of %d pages", len(results)-failed, len(results)))
+	if failed > 0 {
+		return &output.ExitError{Code: 1}
+	}
+	return nil
+}
+
+// pageBatch caches the parents and schemas looked up while building a batch.
+type pageBatch struct {
+	client  *mcp.Client
+	parents map[string]mcp.CreatePageRequest
+	schemas map[string]map[string]mcp.PropertySchema
+}
+
+// request builds the create request for an item. Items without a parent use
+// the --parent or --parent-db given on the command line.
+func (b *pageBatch) request(ctx context.Context, item cli.BatchItem, parent, parentDB string, req *mcp.CreatePageRequest) error {
+	if item.Parent == "" && item.ParentDB == "" {
+		item.Parent, item.ParentDB = parent, parentDB
+	}
+	if err := b.resolveParent(ctx, item.Parent, item.ParentDB, req); err != nil {
+		return err
+	}
+
+	icon, cover, err := resolveIconCover(item.Icon, item.Cover)
+	if err != nil {
+		return err
+	}
+	req.Title = item.Title
+	req.Content = cli.MarkdownToNotion(item.Content)
+	req.Icon = icon
+	req.Cover = cover
+
+	if len(item.Properties) == 0 {
+		return nil
+	}
+	raw, err := item.RawProperties()
+	if err != nil {
+		return err
+	}
+	schema, ok := b.schemas[req.ParentDatabaseID]
+	if !ok {
+		schema, err = dataSourceSchema(ctx, b.client, req.ParentDatabaseID)
+		if err != nil {
+			return err
+		}
+		b.schemas[req.ParentDatabaseID] = schema
+	}
+	req.Properties, err = cli.CoerceProperties(schema, raw)
+	return err
+}
+
+func (b *pageBatch) resolveParent(ctx context.Context, parent, parentDB string, req *mcp.CreatePageRequest) error {
+	key := parent + "|" + parentDB
+	if resolved, ok := b.parents[key]; ok {
+		req.ParentPageID, req.ParentDatabaseID = resolved.ParentPageID, resolved.ParentDatabaseID
+		return nil
+	}
+
+	switch {
+	case parentDB != "":
+		dbID, err := cli.ResolveDatabaseID(ctx, b.client, parentDB)
+		if err != nil {
+			return err
+		}
+		req.ParentDatabaseID, err = b.client.ResolveDataSourceID(ctx, dbID)
+		if err != nil {
+			return err
+		}
+	case parent != "":
+		kind, id, err := cli.ResolveParentID(ctx, b.client, parent)
+		if err != nil {
+			return err
+		}
+		if kind == cli.ParentDatabase {
+			req.ParentDatabaseID = id
+		} else {
+			req.ParentPageID = id
+		}
+	}
+	b.parents[key] = mcp.CreatePageRequest{ParentPageID: req.ParentPageID, ParentDatabaseID: req.ParentDatabaseID}
+	return nil
+}
+
+// create sends the pages at indexes, which share a parent, in one call and
+// records the outcome in results. A page too large for one call is created
+// on its own, in chunks.
+func (b *pageBatch) create(ctx context.Context, reqs []mcp.CreatePageRequest, indexes []int, results []batchResult) {
+	if len(indexes) == 1 {
+		i := indexes[0]
+		resp, err := cli.CreatePage(ctx, b.client, reqs[i])
+		if err != nil {
+			results[i].Status, results[i].Error = "failed", err.Error()
+			return
+		}
+		b.record(&results[i], *resp)
+		return
+	}
+
+	batch := make([]mcp.CreatePageRequest, len(indexes))
+	for j, i := range indexes {
+		batch[j] = reqs[i]
+	}
+	resps, err := b.client.CreatePages(ctx, batch)
+	for j, i := range indexes {
+		if err != nil {
+			results[i].Status, results[i].Error = "failed", err.Error()
+			continue
+		}
+		b.record(&results[i], resps[j])
+	}
+}
+
+// record fills in a result from the server's reply. A reply that couldn't be
+// matched to the item leaves it "unknown": the page was probably created,
+// but there is nothing to say which one it is.
+func (b *pageBatch) record(result *batchResult, resp mcp.CreatePageResponse) {
+	result.URL = resp.URL
+	result.ID = resp.ID
+	if result.ID == "" {
+		result.ID, _ = cli.ExtractNotionUUID(resp.URL)
+	}
+	if result.ID == "" && !b.client.DryRun() {
+		result.Status = "unknown"
+		result.Error = "the server's reply couldn't be matched to this page; check the parent before running it again"
+		return
+	}
+	result.Status = "created"
+}
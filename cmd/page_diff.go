@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"os"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+type PageDiffCmd struct {
+	Source  string `arg:"" help:"Markdown file, or page URL, name, or ID"`
+	Page    string `arg:"" optional:"" help:"Page to compare against (default: notion-id from the file's frontmatter)"`
+	Context int    `help:"Lines of context around changes" short:"U" default:"3"`
+}
+
+func (c *PageDiffCmd) Run(ctx *Context) error {
+	return runPageDiff(ctx, c.Source, c.Page, c.Context)
+}
+
+// runPageDiff shows how the remote page would change if the local side were
+// pushed. It exits with status 1 when the two sides differ.
+func runPageDiff(ctx *Context, source, page string, contextLines int) error {
+	var localText, localName string
+	isFile := false
+	if info, err := os.Stat(source); err == nil && !info.IsDir() {
+		raw, err := os.ReadFile(source)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		fm, body := cli.ParseFrontmatter(string(raw))
+		if page == "" {
+			page = fm.NotionID
+		}
+		if page == "" {
+			return &output.UserError{Message: source + " has no notion-id in its frontmatter; pass the page to compare against"}
+		}
+		localText, localName, isFile = cli.MarkdownToNotion(body), source, true
+	} else if page == "" {
+		return &output.UserError{Message: "specify a markdown file, or two pages to compare"}
+	}
+
+	client, err := cli.RequireClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	bgCtx := context.Background()
+
+	if !isFile {
+		left, err := fetchPageBody(bgCtx, client, source)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		localText, localName = left.Body(), diffLabel(left, source)
+	}
+
+	remote, err := fetchPageBody(bgCtx, client, page)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	diff := cli.UnifiedDiff(
+		diffLabel(remote, page),
+		localName,
+		cli.NormalizeMarkdown(remote.Body()),
+		cli.NormalizeMarkdown(localText),
+		contextLines,
+	)
+	if diff == "" {
+		output.PrintInfo("No differences")
+		return nil
+	}
+
+	output.PrintDiff(diff)
+	return &output.ExitError{Code: 1}
+}
+
+func fetchPageBody(ctx context.Context, client *mcp.Client, page string) (*mcp.FetchResult, error) {
+	pageID, err := cli.ResolvePageID(ctx, client, page)
+	if err != nil {
+		return nil, err
+	}
+	return client.Fetch(ctx, pageID)
+}
+
+func diffLabel(result *mcp.FetchResult, ref string) string {
+	if result.Title != "" {
+		return "notion:" + result.Title
+	}
+	return "notion:" + ref
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+type PageDuplicateCmd struct {
+	Page    string        `arg:"" help:"Page URL, name, or ID to duplicate"`
+	Title   string        `help:"Title for the copy" short:"t"`
+	Parent  string        `help:"Parent page or database URL, name, or ID for the copy" short:"p"`
+	Timeout time.Duration `help:"How long to wait for the copy to become available" default:"60s"`
+	JSON    bool          `help:"Output as JSON" short:"j"`
+}
+
+func (c *PageDuplicateCmd) Run(ctx *Context) error {
+	ctx.JSON = c.JSON
+	return runPageDuplicate(ctx, c.Page, c.Title, c.Parent, c.Timeout)
+}
+
+func runPageDuplicate(ctx *Context, page, title, parent string, timeout time.Duration) error {
+	client, err := cli.RequireClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	bgCtx := context.Background()
+
+	pageID, err := cli.ResolvePageID(bgCtx, client, page)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	var moveReq mcp.MovePagesRequest
+	if parent != "" {
+		kind, parentID, err := cli.ResolveParentID(bgCtx, client, parent)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		if kind == cli.ParentDatabase {
+			moveReq.ParentDatabaseID = parentID
+		} else {
+			moveReq.ParentPageID = parentID
+		}
+	}
+
+	resp, err := client.DuplicatePage(bgCtx, pageID)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+	if client.DryRun() {
+		// Renaming and moving the copy need its ID, which a dry run doesn't have.
+		return nil
+	}
+
+	newID, ok := cli.ExtractNotionUUID(resp.ID)
+	if !ok {
+		err := fmt.Errorf("page duplicated but could not determine the new page ID")
+		output.PrintError(err)
+		return err
+	}
+
+	fetched, err := waitForPage(bgCtx, client, newID, timeout)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	if title != "" {
+		req := mcp.UpdatePageRequest{
+			PageID:     newID,
+			Command:    "update_properties",
+			Properties: map[string]any{"title": title},
+		}
+		if err := client.UpdatePage(bgCtx, req); err != nil {
+			output.PrintError(fmt.Errorf("page duplicated but failed to set title: %w", err))
+			return err
+		}
+	} else {
+		title = fetched.Title
+	}
+
+	if parent != "" {
+		moveReq.PageIDs = []string{newID}
+		if err := client.MovePages(bgCtx, moveReq); err != nil {
+			output.PrintError(fmt.Errorf("page duplicated but failed to move: %w", err))
+			return err
+		}
+	}
+
+	url := resp.URL
+	if url == "" {
+		url = fetched.URL
+	}
+
+	if ctx.JSON {
+		outPage := output.Page{
+			ID:    newID,
+			URL:   url,
+			Title: title,
+		}
+		return output.PrintPage(outPage, true)
+	}
+
+	if url != "" {
+		output.PrintSuccess("Page duplicated: " + url)
+	} else {
+		output.PrintSuccess("Page duplicated: " + newID)
+	}
+	return nil
+}
+
+// waitForPage polls until a page can be fetched or the timeout elapses.
+func waitForPage(ctx context.Context, client *mcp.Client, pageID string, timeout time.Duration) (*mcp.FetchResult, error) {
+	deadline := time.Now().Add(timeout)
+	delay := 500 * time.Millisecond
+	for {
+		result, err := client.Fetch(ctx, pageID)
+		if err == nil && result.Content != "" {
+			return result, nil
+		}
+		if time.Now().Add(delay).After(deadline) {
+			if err == nil {
+				err = fmt.Errorf("page has no content yet")
+			}
+			return nil, fmt.Errorf("timed out waiting for page %s: %w", pageID, err)
+		}
+		time.Sleep(delay)
+		if delay < 5*time.Second {
+			delay *= 2
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+// runPageEditInEditor round-trips a page's content through $EDITOR and applies
+// the changes, using targeted range edits where possible.
+func runPageEditInEditor(ctx context.Context, client *mcp.Client, pageID string) error {
+	fetched, err := client.Fetch(ctx, pageID)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+	original := fetched.Body()
+
+	edited, err := cli.EditText(original+"\n", ".md")
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+	edited = trimFinalNewline(edited)
+
+	if edited == original {
+		output.PrintInfo("No changes")
+		return nil
+	}
+
+	// Make sure nobody else edited the page while the editor was open, so we
+	// don't overwrite their changes.
+	current, err := client.Fetch(ctx, pageID)
+	if err != nil {
+		return keepEdits(edited, err)
+	}
+	if current.Body() != original {
+		return keepEdits(edited, &output.UserError{Message: "page changed on Notion while it was being edited; aborting without saving"})
+	}
+
+	edits, ok := cli.PlanContentEdits(original, edited)
+	if !ok {
+		if err := cli.ReplaceContent(ctx, client, pageID, edited); err != nil {
+			return keepEdits(edited, err)
+		}
+		output.PrintSuccess("Page updated (content replaced)")
+		return nil
+	}
+
+	for i, e := range edits {
+		req := mcp.UpdatePageRequest{
+			PageID:    pageID,
+			Command:   "replace_content_range",
+			Selection: e.Selection,
+			NewStr:    e.NewStr,
+		}
+		if err := client.UpdatePage(ctx, req); err != nil {
+			return keepEdits(edited, fmt.Errorf("applied %d of %d edits: %w", i, len(edits), err))
+		}
+	}
+
+	if len(edits) == 1 {
+		output.PrintSuccess("Page updated (1 edit)")
+	} else {
+		output.PrintSuccess(fmt.Sprintf("Page updated (%d edits)", len(edits)))
+	}
+	return nil
+}
+
+// keepEdits saves text the editor produced but that couldn't be applied, and
+// adds where to find it to err.
+func keepEdits(edited string, err error) error {
+	path, saveErr := cli.SaveText(edited+"\n", ".md")
+	if saveErr != nil {
+		output.PrintWarning("Could not save your edits: " + saveErr.Error())
+	} else {
+		err = fmt.Errorf("%w; your edits are saved in %s", err, path)
+	}
+	output.PrintError(err)
+	return err
+}
+
+func trimFinalNewline(s string) string {
+	if len(s) > 0 && s[len(s)-1] == '\n' {
+		s = s[:len(s)-1]
+	}
+	if len(s) > 0 && s[len(s)-1] == '\r' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+// createPageIfMissing creates the page for req, or, depending on ifExists,
+// skips, updates or refuses to duplicate a page that already exists. It
+// returns what it did: "created", "skipped" or "updated".
+func createPageIfMissing(ctx context.Context, client *mcp.Client, req mcp.CreatePageRequest, ifExists, match string) (string, *mcp.CreatePageResponse, error) {
+	if match != "" && ifExists == cli.IfExistsCreate {
+		return "", nil, &output.UserError{Message: "--match needs --if-exists skip, update or error"}
+	}
+	if ifExists != cli.IfExistsCreate {
+		existing, err := cli.FindExistingPage(ctx, client, req, match)
+		if err != nil {
+			return "", nil, err
+		}
+		if existing != nil {
+			resp := &mcp.CreatePageResponse{ID: existing.ID, URL: existing.URL}
+			switch ifExists {
+			case cli.IfExistsSkip:
+				return "skipped", resp, nil
+			case cli.IfExistsUpdate:
+				if err := updateExistingPage(ctx, client, existing, req); err != nil {
+					return "", nil, err
+				}
+				return "updated", resp, nil
+			default:
+				return "", nil, &output.UserError{Message: fmt.Sprintf("%q already exists: %s", existing.Title, existing.URL)}
+			}
+		}
+	}
+
+	resp, err := cli.CreatePage(ctx, client, req)
+	if err != nil {
+		return "", nil, err
+	}
+	return "created", resp, nil
+}
+
+// updateExistingPage brings an existing page in line with req: its title,
+// properties, icon and cover, and its content when req has any.
+func updateExistingPage(ctx context.Context, client *mcp.Client, existing *cli.ExistingPage, req mcp.CreatePageRequest) error {
+	props := make(map[string]any, len(req.Properties)+1)
+	for k, v := range req.Properties {
+		props[k] = v
+	}
+	if req.Title != "" && req.Title != existing.Title {
+		schema, err := dataSourceSchema(ctx, client, req.ParentDatabaseID)
+		if err != nil {
+			return err
+		}
+		title, _, err := cli.FrontmatterProperties(schema, cli.Frontmatter{Title: req.Title})
+		if err != nil {
+			return err
+		}
+		for k, v := range title {
+			props[k] = v
+		}
+	}
+
+	if len(props) > 0 || req.Icon != "" || req.Cover != "" {
+		err := client.UpdatePage(ctx, mcp.UpdatePageRequest{
+			PageID:     existing.ID,
+			Command:    "update_properties",
+			Properties: props,
+			Icon:       req.Icon,
+			Cover:      req.Cover,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if req.Content == "" {
+		return nil
+	}
+	return cli.ReplaceContent(ctx, client, existing.ID, req.Content)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+type PageMoveCmd struct {
+	Pages []string `arg:"" help:"Page URLs, names, or IDs to move"`
+	To    string   `help:"New parent page or database URL, name, or ID" required:""`
+	JSON  bool     `help:"Output as JSON" short:"j"`
+}
+
+func (c *PageMoveCmd) Run(ctx *Context) error {
+	ctx.JSON = c.JSON
+	return runPageMove(ctx, c.Pages, c.To)
+}
+
+func runPageMove(ctx *Context, pages []string, to string) error {
+	client, err := cli.RequireClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	bgCtx := context.Background()
+
+	kind, parentID, err := cli.ResolveParentID(bgCtx, client, to)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	moves := make([]output.PageMove, 0, len(pages))
+	req := mcp.MovePagesRequest{}
+	if kind == cli.ParentDatabase {
+		req.ParentDatabaseID = parentID
+	} else {
+		req.ParentPageID = parentID
+	}
+
+	for _, page := range pages {
+		pageID, err := cli.ResolvePageID(bgCtx, client, page)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+
+		result, err := client.Fetch(bgCtx, pageID)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+
+		title := result.Title
+		if title == "" {
+			title = page
+		}
+		moves = append(moves, output.PageMove{
+			ID:        pageID,
+			Title:     title,
+			OldParent: result.ParentID,
+			NewParent: parentID,
+		})
+		req.PageIDs = append(req.PageIDs, pageID)
+	}
+
+	if err := client.MovePages(bgCtx, req); err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	if ctx.JSON {
+		return output.PrintPageMoves(moves, true)
+	}
+
+	if err := output.PrintPageMoves(moves, false); err != nil {
+		return err
+	}
+	if len(moves) == 1 {
+		output.PrintSuccess("Moved 1 page")
+	} else {
+		output.PrintSuccess(fmt.Sprintf("Moved %d pages", len(moves)))
+	}
+	return nil
+}
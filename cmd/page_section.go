@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+// runPageEditSection replaces the content under one heading of a page,
+// selecting the section from the fetched content so the rest of the page is
+// left untouched.
+func runPageEditSection(ctx *Context, page, section, content string) error {
+	content, err := cli.ReadContent(content)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	client, err := cli.RequireClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	bgCtx := context.Background()
+
+	pageID, err := cli.ResolvePageID(bgCtx, client, page)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	fetched, err := client.Fetch(bgCtx, pageID)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+	body := fetched.Body()
+
+	s, err := cli.FindSection(body, section)
+	if err != nil {
+		err = &output.UserError{Message: err.Error()}
+		output.PrintError(err)
+		return err
+	}
+
+	edit, err := cli.ReplaceSection(body, s, content)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	req := mcp.UpdatePageRequest{
+		PageID:    pageID,
+		Command:   "replace_content_range",
+		Selection: edit.Selection,
+		NewStr:    edit.NewStr,
+	}
+	if err := client.UpdatePage(bgCtx, req); err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	output.PrintSuccess("Section updated: " + s.Heading)
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+type PageSetCmd struct {
+	Page  string   `arg:"" help:"Page URL, name, or ID"`
+	Prop  []string `help:"Property key=value (repeatable)" short:"P"`
+	Unset []string `help:"Property to clear (repeatable)"`
+	Title string   `help:"New page title" short:"t"`
+	Icon  string   `help:"Page icon: emoji, :shortcode: or image URL" short:"i"`
+	Cover string   `help:"Cover image URL"`
+	JSON  bool     `help:"Output as JSON" short:"j"`
+}
+
+func (c *PageSetCmd) Run(ctx *Context) error {
+	ctx.JSON = c.JSON
+	return runPageSet(ctx, c.Page, c.Prop, c.Unset, c.Title, c.Icon, c.Cover)
+}
+
+func runPageSet(ctx *Context, page string, props, unset []string, title, icon, cover string) error {
+	if len(props) == 0 && len(unset) == 0 && title == "" && icon == "" && cover == "" {
+		return &output.UserError{Message: "specify at least one of --prop, --unset, --title, --icon or --cover"}
+	}
+
+	icon, cover, err := resolveIconCover(icon, cover)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	raw, err := cli.ParsePropertyArgs(props)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	client, err := cli.RequireClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	bgCtx := context.Background()
+
+	pageID, err := cli.ResolvePageID(bgCtx, client, page)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	fetched, err := client.Fetch(bgCtx, pageID)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	schema, err := cli.PageSchema(bgCtx, client, fetched)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	properties, err := cli.CoerceProperties(schema, raw)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	cleared, err := cli.UnsetProperties(schema, unset)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+	for k, v := range cleared {
+		properties[k] = v
+	}
+
+	if title != "" {
+		properties[cli.TitlePropertyKey(schema)] = title
+	}
+
+	req := mcp.UpdatePageRequest{
+		PageID:     pageID,
+		Command:    "update_properties",
+		Properties: properties,
+		Icon:       icon,
+		Cover:      cover,
+	}
+	if err := client.UpdatePage(bgCtx, req); err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	if title == "" {
+		title = fetched.Title
+	}
+
+	if ctx.JSON {
+		outPage := output.Page{
+			ID:    pageID,
+			URL:   fetched.URL,
+			Title: title,
+			Icon:  icon,
+		}
+		return output.PrintPage(outPage, true)
+	}
+
+	output.PrintSuccess("Page updated")
+	return nil
+}
@@ -0,0 +1,535 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+// syncTolerance allows for the file being written moments after the sync
+// timestamp is recorded in it.
+const syncTolerance = 5 * time.Second
+
+type PageSyncCmd struct {
+	Path      string `arg:"" name:"path" help:"Markdown file or directory to sync" type:"path"`
+	Title     string `help:"Page title (default: filename or first heading)" short:"t"`
+	Parent    string `help:"Parent page URL, name, or ID" short:"p"`
+	ParentDB  string `help:"Parent database URL, name, or ID" name:"parent-db" short:"d"`
+	Icon      string `help:"Emoji icon for the page" short:"i"`
+	Pull      bool   `help:"Pull remote changes into the file (same as --direction pull)"`
+	Direction string `help:"Sync direction: push, pull, or auto (compare remote and local changes since the last sync)" enum:"push,pull,auto" default:"auto"`
+	WikiLinks bool   `help:"Write page mentions as [[wiki-links]] when pulling (always on inside an Obsidian vault)" name:"wiki-links"`
+	Prune     bool   `help:"Archive pages whose files were deleted (directory sync)"`
+	Jobs      int    `help:"Number of files to sync concurrently (directory sync)" default:"4"`
+	JSON      bool   `help:"Output as JSON" short:"j"`
+}
+
+func (c *PageSyncCmd) Run(ctx *Context) error {
+	ctx.JSON = c.JSON
+	direction := c.Direction
+	if c.Pull {
+		direction = "pull"
+	}
+
+	info, err := os.Stat(c.Path)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+	if info.IsDir() {
+		if c.Title != "" || c.Icon != "" {
+			return &output.UserError{Message: "--title and --icon apply to single files; set them in each file instead"}
+		}
+		return runDirSync(ctx, c.Path, c.Parent, c.ParentDB, direction, c.WikiLinks, c.Prune, c.Jobs)
+	}
+	return runPageSync(ctx, c.Path, c.Title, c.Parent, c.ParentDB, c.Icon, direction, c.WikiLinks)
+}
+
+type syncOptions struct {
+	File         string
+	PageID       string // page for a file without a notion-id, instead of creating one
+	Title        string
+	Icon         string
+	ParentPageID string
+	ParentDBID   string // data source ID
+	Direction    string
+	WikiLinks    bool
+	Relink       bool // push even when unchanged, because link targets now exist
+	Assets       *assetChecker
+	Links        *pageLinker
+}
+
+// syncConflictError reports a merge that left conflict markers in the file.
+type syncConflictError struct {
+	File      string
+	Conflicts int
+}
+
+func (e *syncConflictError) Error() string {
+	return fmt.Sprintf("%d conflict(s) between %s and the Notion page; resolve the markers in the file, then run: notion-cli page sync --direction push %s", e.Conflicts, e.File, e.File)
+}
+
+func runPageSync(ctx *Context, file, title, parent, parentDB, icon, direction string, wikiLinks bool) error {
+	client, err := cli.RequireClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	bgCtx := context.Background()
+
+	opts := syncOptions{
+		File:      file,
+		Title:     title,
+		Icon:      icon,
+		Direction: direction,
+		WikiLinks: wikiLinks,
+		Assets:    newAssetChecker(),
+		Links:     newPageLinker(client, ""),
+	}
+
+	if parentDB != "" {
+		dbID, err := cli.ResolveDatabaseID(bgCtx, client, parentDB)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		dbID, err = client.ResolveDataSourceID(bgCtx, dbID)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		opts.ParentDBID = dbID
+	} else if parent != "" {
+		parentID, err := cli.ResolvePageID(bgCtx, client, parent)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		opts.ParentPageID = parentID
+	}
+
+	result, err := syncFile(bgCtx, client, opts)
+	opts.Assets.report()
+	opts.Links.report()
+	if err != nil {
+		var conflictErr *syncConflictError
+		if errors.As(err, &conflictErr) {
+			output.PrintWarning(conflictErr.Error())
+			return &output.ExitError{Code: 1}
+		}
+		output.PrintError(err)
+		return err
+	}
+
+	if ctx.JSON {
+		outPage := output.Page{
+			ID:    result.ID,
+			URL:   result.URL,
+			Title: result.Title,
+			Icon:  result.Icon,
+		}
+		return output.PrintPage(outPage, true)
+	}
+
+	switch result.Action {
+	case "created":
+		output.PrintSuccess("Created: " + result.Title)
+		if result.URL != "" {
+			output.PrintInfo(result.URL)
+		}
+	case "pushed":
+		output.PrintSuccess("Synced: " + result.Title)
+	case "pulled":
+		output.PrintSuccess("Pulled: " + result.Title)
+	case "merged":
+		output.PrintSuccess("Merged: " + result.Title)
+	default:
+		output.PrintInfo("Already up to date: " + result.Title)
+	}
+	return nil
+}
+
+// syncFile syncs one markdown file with its page, creating the page if the
+// file has no notion-id yet. The Action in the result is one of created,
+// pushed, pulled, merged or unchanged.
+func syncFile(ctx context.Context, client *mcp.Client, opts syncOptions) (*output.SyncResult, error) {
+	raw, err := os.ReadFile(opts.File)
+	if err != nil {
+		return nil, err
+	}
+
+	content := string(raw)
+	fm, body := cli.ParseFrontmatter(content)
+	if fm.NotionID == "" && opts.PageID != "" {
+		fm.NotionID = opts.PageID
+		content = cli.SetFrontmatterID(content, opts.PageID)
+	}
+
+	// A file that is the body of an existing page only retitles it when
+	// its frontmatter says so.
+	isBody := opts.PageID != "" && fm.NotionID == opts.PageID
+	title, icon := opts.Title, opts.Icon
+	if title == "" {
+		title = fm.Title
+	}
+	if title == "" && !isBody {
+		title = extractTitleFromMarkdown(body)
+	}
+	if title == "" && !isBody {
+		title = strings.TrimSuffix(filepath.Base(opts.File), filepath.Ext(opts.File))
+	}
+	if icon == "" {
+		icon = fm.Icon
+	}
+	if icon == "" {
+		icon, title = extractEmojiFromTitle(title)
+	}
+	icon, fm.Cover, err = resolveIconCover(icon, fm.Cover)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &output.SyncResult{
+		File:  opts.File,
+		ID:    fm.NotionID,
+		Title: title,
+		Icon:  icon,
+	}
+	if icon != "" {
+		result.Title = icon + " " + title
+	}
+
+	direction := opts.Direction
+	if fm.NotionID == "" {
+		if direction == "pull" {
+			return nil, &output.UserError{Message: opts.File + " has no notion-id in its frontmatter; nothing to pull"}
+		}
+		result.ID, result.URL, err = createSyncedPage(ctx, client, opts, fm, content, body, title, icon)
+		if err != nil {
+			return nil, err
+		}
+		result.Action = "created"
+		opts.Links.register(opts.File, result.ID)
+		return result, nil
+	}
+
+	var remote *mcp.FetchResult
+	if direction != "push" {
+		remote, err = client.Fetch(ctx, fm.NotionID)
+		if err != nil {
+			return nil, err
+		}
+		result.URL = remote.URL
+	}
+
+	snap, hasSnap, err := cli.LoadSyncSnapshot(fm.NotionID)
+	if err != nil {
+		output.PrintWarning("Ignoring sync snapshot: " + err.Error())
+		hasSnap = false
+	}
+
+	if direction == "auto" {
+		if hasSnap {
+			direction = snapshotDirection(body, remote, snap)
+		} else {
+			direction, err = syncDirection(ctx, opts, fm, body, remote)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if direction == "none" && opts.Relink {
+			direction = "push"
+		}
+	}
+
+	switch direction {
+	case "pull":
+		if err := pullPage(ctx, opts, content, fm.NotionID, remote); err != nil {
+			return nil, err
+		}
+		result.Action = "pulled"
+	case "push":
+		if err := pushPage(ctx, client, opts, content, fm.NotionID, body); err != nil {
+			return nil, err
+		}
+		if err := pushPageAttributes(ctx, client, opts.File, fm, remote, title, icon); err != nil {
+			return nil, err
+		}
+		result.Action = "pushed"
+	case "merge":
+		// Compare the page with the Markdown its last synced version renders
+		// to, not with the file, so that formatting the file and Notion
+		// write differently isn't taken for remote edits.
+		remoteBase := remoteMarkdown(ctx, opts, snap.Remote) + "\n"
+		remoteBody := remoteMarkdown(ctx, opts, remote.Body()) + "\n"
+		merged, conflicts := cli.Merge3Rebased(snap.Base, body, remoteBase, remoteBody, opts.File, "notion")
+		if conflicts > 0 {
+			if err := writeSyncedFile(opts.File, cli.ReplaceBody(content, merged)); err != nil {
+				return nil, err
+			}
+			return nil, &syncConflictError{File: opts.File, Conflicts: conflicts}
+		}
+		if err := pushPage(ctx, client, opts, cli.ReplaceBody(content, merged), fm.NotionID, merged); err != nil {
+			return nil, err
+		}
+		if err := pushPageAttributes(ctx, client, opts.File, fm, remote, title, icon); err != nil {
+			return nil, err
+		}
+		result.Action = "merged"
+	default:
+		result.Action = "unchanged"
+	}
+
+	opts.Links.register(opts.File, result.ID)
+	return result, nil
+}
+
+// snapshotDirection compares both sides with the snapshot from the last sync.
+// It returns "merge" when both changed and "none" when neither did.
+func snapshotDirection(body string, remote *mcp.FetchResult, snap *cli.SyncSnapshot) string {
+	localChanged := body != snap.Base
+	remoteChanged := remote.Body() != snap.Remote
+
+	switch {
+	case localChanged && remoteChanged:
+		return "merge"
+	case remoteChanged:
+		return "pull"
+	case localChanged:
+		return "push"
+	}
+	return "none"
+}
+
+// syncDirection decides which way an auto sync goes when there is no snapshot,
+// by comparing the page's last edit and the file's modification time with the
+// last sync recorded in frontmatter. It returns "none" when neither changed.
+func syncDirection(ctx context.Context, opts syncOptions, fm cli.Frontmatter, body string, remote *mcp.FetchResult) (string, error) {
+	file := opts.File
+	if fm.SyncedAt.IsZero() || remote.LastEditedTime.IsZero() {
+		// Nothing says which side is newer. The file is taken as the source,
+		// as it always has been, but there is no need to push a file the
+		// page already matches.
+		if strings.TrimSpace(cli.NormalizeMarkdown(body)) == strings.TrimSpace(cli.NormalizeMarkdown(remoteMarkdown(ctx, opts, remote.Body()))) {
+			return "none", nil
+		}
+		return "push", nil
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return "", err
+	}
+
+	remoteChanged := remote.LastEditedTime.After(fm.SyncedAt)
+	localChanged := info.ModTime().After(fm.SyncedAt.Add(syncTolerance))
+
+	switch {
+	case remoteChanged && localChanged:
+		return "", &output.UserError{Message: "both " + file + " and the Notion page changed since the last sync; use --direction push or --direction pull to choose"}
+	case remoteChanged:
+		return "pull", nil
+	case localChanged:
+		return "push", nil
+	}
+	return "none", nil
+}
+
+// pushPage replaces the page content with body, then records the sync in the
+// file's frontmatter and the sync snapshot. content is the full file to write.
+func pushPage(ctx context.Context, client *mcp.Client, opts syncOptions, content, pageID, body string) error {
+	file := opts.File
+	if cli.HasConflictMarkers(body) {
+		return &output.UserError{Message: file + " contains unresolved conflict markers"}
+	}
+
+	newContent := opts.Links.rewrite(ctx, file, cli.MarkdownToNotion(body))
+	opts.Assets.check(file, newContent)
+	if err := cli.ReplaceContent(ctx, client, pageID, newContent); err != nil {
+		return err
+	}
+
+	updated := cli.SetFrontmatterSyncedAt(content, time.Now())
+	if err := writeSyncedFile(file, updated); err != nil {
+		return fmt.Errorf("page synced but failed to update frontmatter: %w", err)
+	}
+	saveSyncSnapshot(ctx, client, pageID, updated, nil)
+	return nil
+}
+
+// pullPage rewrites the file body with the remote page content, keeping the
+// frontmatter and recording the sync.
+func pullPage(ctx context.Context, opts syncOptions, content, pageID string, remote *mcp.FetchResult) error {
+	body := remoteMarkdown(ctx, opts, remote.Body())
+	updated := cli.ReplaceBody(content, body+"\n")
+	updated = cli.SetFrontmatterSyncedAt(updated, time.Now())
+	if err := writeSyncedFile(opts.File, updated); err != nil {
+		return err
+	}
+	saveSyncSnapshot(context.Background(), nil, pageID, updated, remote)
+	return nil
+}
+
+// remoteMarkdown converts page content to Markdown for the file, turning
+// page mentions into wiki-links when asked to or inside an Obsidian vault.
+func remoteMarkdown(ctx context.Context, opts syncOptions, content string) string {
+	if opts.Links != nil && (opts.WikiLinks || cli.VaultRoot(opts.File) != "") {
+		content = cli.MentionsToWikiLinks(content, opts.Links.wikiName(ctx, opts.File))
+		content = cli.NotionLinksToWikiLinks(content, opts.Links.vaultName(opts.File))
+	}
+	return output.NotionToMarkdown(content)
+}
+
+// saveSyncSnapshot records the file as written and the page as Notion now has
+// it. If remote is nil the page is fetched. Failures only produce a warning:
+// the next sync falls back to comparing timestamps.
+func saveSyncSnapshot(ctx context.Context, client *mcp.Client, pageID, written string, remote *mcp.FetchResult) {
+	if cli.DryRun() {
+		return
+	}
+	if remote == nil {
+		var err error
+		remote, err = client.Fetch(ctx, pageID)
+		if err != nil {
+			output.PrintWarning("Could not record sync snapshot: " + err.Error())
+			return
+		}
+	}
+
+	_, base := cli.ParseFrontmatter(written)
+	snap := cli.SyncSnapshot{
+		Base:     base,
+		Remote:   remote.Body(),
+		SyncedAt: time.Now(),
+	}
+	if err := cli.SaveSyncSnapshot(pageID, snap); err != nil {
+		output.PrintWarning("Could not record sync snapshot: " + err.Error())
+	}
+}
+
+// pushPageAttributes updates the page's title, icon, cover and frontmatter
+// properties from the file, so they sync along with the content. The title is
+// only sent when it differs from the page's. remote may be nil, in which case
+// the page is fetched.
+func pushPageAttributes(ctx context.Context, client *mcp.Client, file string, fm cli.Frontmatter, remote *mcp.FetchResult, title, icon string) error {
+	if remote == nil {
+		var err error
+		remote, err = client.Fetch(ctx, fm.NotionID)
+		if err != nil {
+			return err
+		}
+	}
+
+	schema, err := cli.PageSchema(ctx, client, remote)
+	if err != nil {
+		return err
+	}
+	fm.Title = ""
+	if title != "" && title != remote.Title {
+		fm.Title = title
+	}
+	props, err := frontmatterProperties(file, schema, fm, true)
+	if err != nil {
+		return err
+	}
+	if len(props) == 0 && icon == "" && fm.Cover == "" {
+		return nil
+	}
+
+	return client.UpdatePage(ctx, mcp.UpdatePageRequest{
+		PageID:     fm.NotionID,
+		Command:    "update_properties",
+		Properties: props,
+		Icon:       icon,
+		Cover:      fm.Cover,
+	})
+}
+
+// createSyncedPage creates the page for a file and records its notion-id in
+// the file's frontmatter. Without a parent in opts, the parent named in the
+// frontmatter is used.
+func createSyncedPage(ctx context.Context, client *mcp.Client, opts syncOptions, fm cli.Frontmatter, content, body, title, icon string) (id, url string, err error) {
+	req := mcp.CreatePageRequest{
+		Title:            title,
+		Content:          opts.Links.rewrite(ctx, opts.File, cli.MarkdownToNotion(body)),
+		Icon:             icon,
+		Cover:            fm.Cover,
+		ParentPageID:     opts.ParentPageID,
+		ParentDatabaseID: opts.ParentDBID,
+	}
+	opts.Assets.check(opts.File, req.Content)
+
+	if req.ParentPageID == "" && req.ParentDatabaseID == "" {
+		req.ParentPageID, req.ParentDatabaseID, err = resolveFrontmatterParent(ctx, client, fm)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if len(fm.Properties) > 0 {
+		schema, err := dataSourceSchema(ctx, client, req.ParentDatabaseID)
+		if err != nil {
+			return "", "", err
+		}
+		req.Properties, err = frontmatterProperties(opts.File, schema, fm, false)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	resp, err := cli.CreatePage(ctx, client, req)
+	if err != nil {
+		return "", "", err
+	}
+
+	pageID := resp.ID
+	if pageID == "" && resp.URL != "" {
+		pageID, _ = cli.ExtractNotionUUID(resp.URL)
+	}
+	if pageID == "" && client.DryRun() {
+		// Stand in for the new page so pages nested under it can be shown.
+		pageID = dryRunPageID(opts.File)
+	}
+	if pageID == "" {
+		output.PrintWarning("Page created but could not retrieve ID for frontmatter: " + opts.File)
+		return "", resp.URL, nil
+	}
+
+	updated := cli.SetFrontmatterID(content, pageID)
+	updated = cli.SetFrontmatterSyncedAt(updated, time.Now())
+	if err := writeSyncedFile(opts.File, updated); err != nil {
+		return pageID, resp.URL, fmt.Errorf("page created but failed to update frontmatter: %w", err)
+	}
+	saveSyncSnapshot(ctx, client, pageID, updated, nil)
+
+	return pageID, resp.URL, nil
+}
+
+// dryRunPageID stands in for the ID of a page a dry run would create.
+func dryRunPageID(name string) string {
+	return "<new page: " + name + ">"
+}
+
+// writeSyncedFile writes content to file, keeping its existing permissions.
+// A dry run only reports the write.
+func writeSyncedFile(file, content string) error {
+	if cli.DryRun() {
+		output.PrintProgress("[dry-run] write " + file)
+		return nil
+	}
+
+	fileMode := os.FileMode(0o644)
+	if info, err := os.Stat(file); err == nil {
+		fileMode = info.Mode()
+	}
+	return os.WriteFile(file, []byte(content), fileMode)
+}
@@ -0,0 +1,584 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+// dirManifestPath is where a directory sync records the pages it created,
+// relative to the synced directory.
+const dirManifestPath = ".notion-cli/sync.json"
+
+// indexFiles become the page for the folder that contains them.
+var indexFiles = []string{"index.md", "README.md"}
+
+// dirManifest maps slash-separated paths relative to the synced directory to
+// page IDs. Folders are recorded so folders without an index file keep their
+// page; files are recorded so deleted files can be pruned.
+type dirManifest struct {
+	Folders map[string]string `json:"folders"`
+	Files   map[string]string `json:"files"`
+}
+
+func loadDirManifest(root string) (*dirManifest, error) {
+	m := &dirManifest{Folders: map[string]string{}, Files: map[string]string{}}
+	data, err := os.ReadFile(filepath.Join(root, dirManifestPath))
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", dirManifestPath, err)
+	}
+	if m.Folders == nil {
+		m.Folders = map[string]string{}
+	}
+	if m.Files == nil {
+		m.Files = map[string]string{}
+	}
+	return m, nil
+}
+
+func saveDirManifest(root string, m *dirManifest) error {
+	path := filepath.Join(root, dirManifestPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// syncTree is the markdown layout of a directory: each folder's index file
+// (if any) and the other markdown files it contains. Folders without
+// Markdown anywhere below them, such as image folders, are left out.
+type syncTree struct {
+	Folders []string            // relative folder paths, excluding the root
+	Index   map[string]string   // folder -> index file; "." for the root
+	Files   map[string][]string // folder -> non-index markdown files
+}
+
+func scanSyncTree(root string) (*syncTree, error) {
+	tree := &syncTree{
+		Index: map[string]string{},
+		Files: map[string][]string{},
+	}
+
+	var dirs []string
+	hasMarkdown := map[string]bool{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if rel != "." && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if rel != "." {
+				dirs = append(dirs, rel)
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(path), ".md") {
+			return nil
+		}
+
+		dir := filepath.ToSlash(filepath.Dir(rel))
+		for f := dir; f != "." && !hasMarkdown[f]; f = parentFolder(f) {
+			hasMarkdown[f] = true
+		}
+		if isIndexFile(d.Name()) && tree.Index[dir] == "" {
+			tree.Index[dir] = rel
+			return nil
+		}
+		tree.Files[dir] = append(tree.Files[dir], rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		if hasMarkdown[dir] {
+			tree.Folders = append(tree.Folders, dir)
+		}
+	}
+	return tree, nil
+}
+
+func isIndexFile(name string) bool {
+	for _, idx := range indexFiles {
+		if name == idx {
+			return true
+		}
+	}
+	return false
+}
+
+func folderDepth(rel string) int {
+	return strings.Count(rel, "/")
+}
+
+func parentFolder(rel string) string {
+	return filepath.ToSlash(filepath.Dir(rel))
+}
+
+func runDirSync(ctx *Context, root, parent, parentDB, direction string, wikiLinks, prune bool, jobs int) error {
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	client, err := cli.RequireClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	bgCtx := context.Background()
+
+	rootOpts := syncOptions{
+		Direction: direction,
+		WikiLinks: wikiLinks,
+		Assets:    newAssetChecker(),
+		Links:     newPageLinker(client, root),
+	}
+	if parentDB != "" {
+		dbID, err := cli.ResolveDatabaseID(bgCtx, client, parentDB)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		dbID, err = client.ResolveDataSourceID(bgCtx, dbID)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		rootOpts.ParentDBID = dbID
+	} else if parent != "" {
+		parentID, err := cli.ResolvePageID(bgCtx, client, parent)
+		if err != nil {
+			output.PrintError(err)
+			return err
+		}
+		rootOpts.ParentPageID = parentID
+	}
+
+	manifest, err := loadDirManifest(root)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+	tree, err := scanSyncTree(root)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	s := &dirSync{
+		client:   client,
+		root:     root,
+		rootOpts: rootOpts,
+		manifest: manifest,
+		folders:  map[string]string{},
+		jobs:     jobs,
+	}
+
+	// The root's index file is the body of the page the directory is synced
+	// into. Without a parent page there is no such page, so it is synced as
+	// an ordinary file.
+	if index := tree.Index["."]; index != "" {
+		if rootOpts.ParentPageID != "" {
+			s.syncRootIndex(bgCtx, index)
+		} else {
+			tree.Files["."] = append(tree.Files["."], index)
+		}
+	}
+
+	// Folder pages must exist before anything inside them, so create them one
+	// depth at a time.
+	levels := map[int][]string{}
+	maxDepth := -1
+	for _, f := range tree.Folders {
+		d := folderDepth(f)
+		levels[d] = append(levels[d], f)
+		if d > maxDepth {
+			maxDepth = d
+		}
+	}
+	for d := 0; d <= maxDepth; d++ {
+		s.each(levels[d], func(folder string) {
+			s.syncFolder(bgCtx, folder, tree.Index[folder])
+		})
+	}
+
+	var files []string
+	for _, inFolder := range tree.Files {
+		files = append(files, inFolder...)
+	}
+	s.each(files, func(rel string) {
+		s.syncMarkdown(bgCtx, rel, parentFolder(rel))
+	})
+
+	// Files linking to pages created later in this run were pushed with those
+	// links unresolved; push them again now that the pages exist.
+	s.each(rootOpts.Links.pending(), func(file string) {
+		s.relink(bgCtx, file)
+	})
+	rootOpts.Assets.report()
+	rootOpts.Links.report()
+
+	s.prune(bgCtx, tree, prune)
+
+	if !cli.DryRun() {
+		if err := saveDirManifest(root, manifest); err != nil {
+			output.PrintWarning("Could not save " + dirManifestPath + ": " + err.Error())
+		}
+	}
+
+	sort.Slice(s.results, func(i, j int) bool {
+		return s.results[i].File < s.results[j].File
+	})
+	if err := output.PrintSyncResults(s.results, ctx.JSON); err != nil {
+		return err
+	}
+
+	if s.failed > 0 {
+		return &output.ExitError{Code: 1}
+	}
+	return nil
+}
+
+// dirSync holds the state shared by the workers of a directory sync.
+type dirSync struct {
+	client   *mcp.Client
+	root     string
+	rootOpts syncOptions
+	jobs     int
+
+	mu       sync.Mutex
+	manifest *dirManifest
+	folders  map[string]string // folder -> page ID for this run
+	results  []output.SyncResult
+	failed   int
+}
+
+// each runs fn for every item using at most s.jobs goroutines.
+func (s *dirSync) each(items []string, fn func(string)) {
+	sem := make(chan struct{}, s.jobs)
+	var wg sync.WaitGroup
+	for _, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(item string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(item)
+		}(item)
+	}
+	wg.Wait()
+}
+
+// parentOpts returns sync options placing a page inside folder. Pages inside
+// a folder whose page couldn't be synced are skipped.
+func (s *dirSync) parentOpts(folder string) (syncOptions, bool) {
+	if folder == "." {
+		return s.rootOpts, true
+	}
+	s.mu.Lock()
+	id := s.folders[folder]
+	s.mu.Unlock()
+	if id == "" {
+		return syncOptions{}, false
+	}
+	opts := s.rootOpts
+	opts.ParentPageID, opts.ParentDBID = id, ""
+	return opts, true
+}
+
+func (s *dirSync) record(r output.SyncResult, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, r)
+	if failed {
+		s.failed++
+	}
+}
+
+// syncFolder makes sure a folder has a page: its index file if it has one,
+// otherwise an empty page titled with the folder name.
+func (s *dirSync) syncFolder(ctx context.Context, folder, index string) {
+	if index != "" {
+		if id := s.syncMarkdown(ctx, index, parentFolder(folder)); id != "" {
+			s.mu.Lock()
+			s.folders[folder] = id
+			delete(s.manifest.Folders, folder)
+			s.mu.Unlock()
+		}
+		return
+	}
+
+	// Without its index file the folder keeps the page the index file had,
+	// since the folder's other pages live under it.
+	s.mu.Lock()
+	id := s.manifest.Folders[folder]
+	if id == "" {
+		for _, name := range indexFiles {
+			if id = s.manifest.Files[folder+"/"+name]; id != "" {
+				s.manifest.Folders[folder] = id
+				break
+			}
+		}
+	}
+	s.mu.Unlock()
+	if id != "" {
+		s.mu.Lock()
+		s.folders[folder] = id
+		s.mu.Unlock()
+		return
+	}
+
+	result := output.SyncResult{File: folder + "/", Title: filepath.Base(folder)}
+	opts, ok := s.parentOpts(parentFolder(folder))
+	if !ok {
+		result.Action = "skipped"
+		s.record(result, true)
+		return
+	}
+
+	resp, err := s.client.CreatePage(ctx, mcp.CreatePageRequest{
+		Title:            result.Title,
+		ParentPageID:     opts.ParentPageID,
+		ParentDatabaseID: opts.ParentDBID,
+	})
+	if err == nil && resp.ID == "" {
+		resp.ID, _ = cli.ExtractNotionUUID(resp.URL)
+	}
+	if err == nil && resp.ID == "" && s.client.DryRun() {
+		resp.ID = dryRunPageID(folder)
+	}
+	if err != nil || resp.ID == "" {
+		if err == nil {
+			err = errors.New("could not retrieve ID of created page")
+		}
+		output.PrintWarning(folder + ": " + err.Error())
+		result.Action = "failed"
+		s.record(result, true)
+		return
+	}
+
+	result.ID, result.URL, result.Action = resp.ID, resp.URL, "created"
+	s.mu.Lock()
+	s.folders[folder] = resp.ID
+	s.manifest.Folders[folder] = resp.ID
+	s.mu.Unlock()
+	s.record(result, false)
+}
+
+// syncRootIndex syncs the root's index file with the parent page. It isn't
+// recorded in the manifest, so prune never archives the parent.
+func (s *dirSync) syncRootIndex(ctx context.Context, rel string) {
+	opts := s.rootOpts
+	opts.File = filepath.Join(s.root, filepath.FromSlash(rel))
+	opts.PageID = s.rootOpts.ParentPageID
+
+	result, err := syncFile(ctx, s.client, opts)
+	if err != nil {
+		output.PrintWarning(err.Error())
+		s.record(output.SyncResult{File: rel, Action: "failed"}, true)
+		return
+	}
+	result.File = rel
+	s.record(*result, false)
+}
+
+// syncMarkdown syncs one file into folder and returns its page ID, or "" if
+// it failed.
+func (s *dirSync) syncMarkdown(ctx context.Context, rel, folder string) string {
+	opts, ok := s.parentOpts(folder)
+	if !ok {
+		s.record(output.SyncResult{File: rel, Action: "skipped"}, true)
+		return ""
+	}
+	opts.File = filepath.Join(s.root, filepath.FromSlash(rel))
+
+	result, err := syncFile(ctx, s.client, opts)
+	if err != nil {
+		action := "failed"
+		var conflictErr *syncConflictError
+		if errors.As(err, &conflictErr) {
+			action = "conflict"
+		}
+		output.PrintWarning(err.Error())
+		s.record(output.SyncResult{File: rel, Action: action}, true)
+		return ""
+	}
+
+	result.File = rel
+	if result.ID != "" {
+		s.mu.Lock()
+		// A file synced under a new path, such as after a rename, no longer
+		// owns its page under the old one.
+		for old, id := range s.manifest.Files {
+			if old != rel && normalizeID(id) == normalizeID(result.ID) {
+				delete(s.manifest.Files, old)
+			}
+		}
+		s.manifest.Files[rel] = result.ID
+		s.mu.Unlock()
+	}
+	s.record(*result, false)
+	return result.ID
+}
+
+// relink syncs a file again so its links to other files resolve. It goes
+// the usual direction, pushing when neither side changed since the first
+// pass.
+func (s *dirSync) relink(ctx context.Context, file string) {
+	if s.rootOpts.Direction == "pull" {
+		return
+	}
+	opts := s.rootOpts
+	opts.File = file
+	opts.Relink = true
+	if _, err := syncFile(ctx, s.client, opts); err != nil {
+		rel, _ := filepath.Rel(s.root, file)
+		output.PrintWarning(err.Error())
+		s.record(output.SyncResult{File: filepath.ToSlash(rel), Action: "failed"}, true)
+	}
+}
+
+// prune handles pages whose files or folders no longer exist. With archive
+// set they are archived; otherwise they are reported as orphaned. Pages that
+// a present file or folder still uses are only dropped from the manifest,
+// and a page with synced pages under it is never archived.
+func (s *dirSync) prune(ctx context.Context, tree *syncTree, archive bool) {
+	present := map[string]bool{}
+	for _, f := range tree.Folders {
+		present[f] = true
+	}
+	for _, idx := range tree.Index {
+		present[idx] = true
+	}
+	for _, files := range tree.Files {
+		for _, f := range files {
+			present[f] = true
+		}
+	}
+
+	live := map[string]bool{}
+	if s.rootOpts.ParentPageID != "" {
+		live[normalizeID(s.rootOpts.ParentPageID)] = true
+	}
+	for rel, id := range s.manifest.Files {
+		if present[rel] {
+			live[normalizeID(id)] = true
+		}
+	}
+	for rel, id := range s.manifest.Folders {
+		if present[rel] {
+			live[normalizeID(id)] = true
+		}
+	}
+	for _, id := range s.folders {
+		live[normalizeID(id)] = true
+	}
+
+	type gone struct {
+		rel, id string
+		folder  bool
+	}
+	var missing []gone
+	for rel, id := range s.manifest.Files {
+		if present[rel] {
+			continue
+		}
+		if live[normalizeID(id)] {
+			delete(s.manifest.Files, rel)
+			continue
+		}
+		missing = append(missing, gone{rel, id, false})
+	}
+	for rel, id := range s.manifest.Folders {
+		if present[rel] {
+			continue
+		}
+		if live[normalizeID(id)] {
+			delete(s.manifest.Folders, rel)
+			continue
+		}
+		missing = append(missing, gone{rel + "/", id, true})
+	}
+
+	for _, g := range missing {
+		result := output.SyncResult{File: g.rel, ID: g.id, Action: "orphaned"}
+		if archive && s.hasLiveChildren(ctx, g.id, live) {
+			output.PrintWarning(g.rel + ": not archived, because synced pages are still under it")
+			s.record(result, false)
+			continue
+		}
+		if archive {
+			if err := cli.SetArchived(ctx, s.client, g.id, true); err != nil {
+				output.PrintWarning(g.rel + ": " + err.Error())
+				s.record(result, true)
+				continue
+			}
+			result.Action = "archived"
+			if g.folder {
+				delete(s.manifest.Folders, strings.TrimSuffix(g.rel, "/"))
+			} else {
+				delete(s.manifest.Files, g.rel)
+			}
+		}
+		s.record(result, false)
+	}
+
+	if !archive && len(missing) > 0 {
+		output.PrintWarning(fmt.Sprintf("%d page(s) no longer have a local file; use --prune to archive them", len(missing)))
+	}
+}
+
+// hasLiveChildren reports whether any of a page's child pages is one the sync
+// still uses, so archiving the page would take them with it. If the page
+// can't be read it assumes so.
+func (s *dirSync) hasLiveChildren(ctx context.Context, pageID string, live map[string]bool) bool {
+	page, err := s.client.Fetch(ctx, pageID)
+	if err != nil {
+		return true
+	}
+	for _, child := range page.Children() {
+		if live[normalizeID(child.ID)] {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeID returns id in dashed UUID form, so IDs from URLs, frontmatter
+// and tool replies compare equal.
+func normalizeID(id string) string {
+	if uuid, ok := cli.ExtractNotionUUID(id); ok {
+		return uuid
+	}
+	return id
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+// expandPageTemplate reads a template from a local file or a Notion page and
+// fills in its {{placeholders}}. Values come from vars (key=value), then the
+// template's frontmatter vars, then the built-in variables. It returns the
+// template's frontmatter, with the title defaulting to the template page's
+// title or first heading, and the body to create the page with.
+func expandPageTemplate(ctx context.Context, client *mcp.Client, template string, vars []string) (cli.Frontmatter, string, error) {
+	flagVars, err := cli.ParseTemplateVars(vars)
+	if err != nil {
+		return cli.Frontmatter{}, "", err
+	}
+
+	text, title, err := readPageTemplate(ctx, client, template)
+	if err != nil {
+		return cli.Frontmatter{}, "", err
+	}
+
+	defaults, err := cli.TemplateVarDefaults(text)
+	if err != nil {
+		return cli.Frontmatter{}, "", err
+	}
+	values := cli.BuiltinTemplateVars(time.Now(), "")
+	for k, v := range defaults {
+		values[k] = v
+	}
+	for k, v := range flagVars {
+		values[k] = v
+	}
+	if values["user"] == "" && (cli.TemplateUses(text, "user") || cli.TemplateUses(title, "user")) {
+		values["user"] = currentUserName(ctx, client)
+	}
+
+	expanded, err := cli.ExpandTemplate(text, values)
+	if err != nil {
+		return cli.Frontmatter{}, "", err
+	}
+	if err := cli.CheckFrontmatter(expanded); err != nil {
+		return cli.Frontmatter{}, "", fmt.Errorf("template: %w", err)
+	}
+	fm, body := cli.ParseFrontmatter(expanded)
+
+	if fm.Title == "" {
+		fm.Title, err = cli.ExpandTemplate(title, values)
+		if err != nil {
+			return cli.Frontmatter{}, "", err
+		}
+	}
+	if fm.Title == "" {
+		fm.Title = extractTitleFromMarkdown(body)
+	}
+	return fm, body, nil
+}
+
+// readPageTemplate returns the text of a template, and the title of the page
+// when the template is a Notion page.
+func readPageTemplate(ctx context.Context, client *mcp.Client, template string) (text, title string, err error) {
+	if info, err := os.Stat(template); err == nil && !info.IsDir() {
+		data, err := os.ReadFile(template)
+		if err != nil {
+			return "", "", err
+		}
+		return string(data), "", nil
+	}
+
+	pageID, err := cli.ResolvePageID(ctx, client, template)
+	if err != nil {
+		return "", "", err
+	}
+	fetched, err := client.Fetch(ctx, pageID)
+	if err != nil {
+		return "", "", err
+	}
+	return fetched.Body(), fetched.Title, nil
+}
+
+// currentUserName returns the Notion user's name for {{user}}, falling back
+// to the local account name when Notion can't say.
+func currentUserName(ctx context.Context, client *mcp.Client) string {
+	name, err := client.CurrentUser(ctx)
+	if err == nil {
+		return name
+	}
+	output.PrintWarning("Could not look up your Notion user (" + err.Error() + "); using the local user name for {{user}}")
+	if u, err := user.Current(); err == nil {
+		if u.Name != "" {
+			return u.Name
+		}
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
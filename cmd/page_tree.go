@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+type PageTreeCmd struct {
+	Page  string `arg:"" help:"Page URL, name, or ID"`
+	Depth int    `help:"Maximum depth to descend (0 for unlimited)" short:"d" default:"3"`
+	Jobs  int    `help:"Number of pages to fetch at once" default:"8"`
+	JSON  bool   `help:"Output as JSON" short:"j"`
+}
+
+func (c *PageTreeCmd) Run(ctx *Context) error {
+	ctx.JSON = c.JSON
+	return runPageTree(ctx, c.Page, c.Depth, c.Jobs)
+}
+
+func runPageTree(ctx *Context, page string, depth, jobs int) error {
+	if depth < 0 {
+		return &output.UserError{Message: "--depth must not be negative"}
+	}
+	if jobs < 1 {
+		jobs = 1
+	}
+
+	client, err := cli.RequireClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	bgCtx := context.Background()
+
+	pageID, err := cli.ResolvePageID(bgCtx, client, page)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	result, err := client.Fetch(bgCtx, pageID)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	root := &output.PageTreeNode{
+		ID:    pageID,
+		Title: result.Title,
+		Type:  "page",
+		URL:   result.URL,
+	}
+	if result.Type == "database" {
+		root.Type = "database"
+	}
+
+	rootID, _ := cli.ExtractNotionUUID(pageID)
+	walker := &treeWalker{client: client, jobs: jobs, seen: map[string]bool{rootID: true}}
+	if root.Type == "page" {
+		walker.addChildren(root, result)
+	}
+
+	level := root.Children
+	for d := 2; len(level) > 0 && (depth == 0 || d <= depth); d++ {
+		level = walker.expand(bgCtx, level)
+	}
+
+	return output.PrintPageTree(root, ctx.JSON)
+}
+
+// treeWalker fetches the pages of a tree one level at a time, with up to jobs
+// fetches in flight.
+type treeWalker struct {
+	client *mcp.Client
+	jobs   int
+
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// expand fetches the pages in level, attaches their children and returns the
+// children, which make up the next level. Databases are not expanded. Pages
+// that can't be fetched stay in the tree as leaves, with a warning.
+func (w *treeWalker) expand(ctx context.Context, level []*output.PageTreeNode) []*output.PageTreeNode {
+	sem := make(chan struct{}, w.jobs)
+	var wg sync.WaitGroup
+	for _, node := range level {
+		if node.Type != "page" || node.ID == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(node *output.PageTreeNode) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result, err := w.client.Fetch(ctx, node.ID)
+			if err != nil {
+				output.PrintWarning("Could not fetch " + node.Title + ": " + err.Error())
+				return
+			}
+			w.addChildren(node, result)
+		}(node)
+	}
+	wg.Wait()
+
+	var next []*output.PageTreeNode
+	for _, node := range level {
+		next = append(next, node.Children...)
+	}
+	return next
+}
+
+// addChildren attaches the child pages and databases found in result's
+// content to node, skipping any already in the tree.
+func (w *treeWalker) addChildren(node *output.PageTreeNode, result *mcp.FetchResult) {
+	for _, child := range result.Children() {
+		id, _ := cli.ExtractNotionUUID(child.ID)
+		if id != "" {
+			w.mu.Lock()
+			seen := w.seen[id]
+			w.seen[id] = true
+			w.mu.Unlock()
+			if seen {
+				continue
+			}
+		}
+		node.Children = append(node.Children, &output.PageTreeNode{
+			ID:    id,
+			Title: child.Title,
+			Icon:  child.Icon,
+			Type:  child.Type,
+			URL:   child.URL,
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+type PageWatchCmd struct {
+	Page     string        `arg:"" help:"Page URL, name, or ID"`
+	Interval time.Duration `help:"How often to check the page" short:"n" default:"30s"`
+	Exec     string        `help:"Shell command to run on each change, with the change as JSON on stdin" short:"x"`
+	Context  int           `help:"Lines of context around changes" short:"U" default:"3"`
+	JSON     bool          `help:"Print each change as a line of JSON instead of a diff" short:"j"`
+}
+
+func (c *PageWatchCmd) Run(ctx *Context) error {
+	ctx.JSON = c.JSON
+	return runPageWatch(ctx, c.Page, c.Interval, c.Exec, c.Context)
+}
+
+// pageChange describes one change seen by page watch. It is printed with
+// --json and passed to the --exec command.
+type pageChange struct {
+	PageID     string               `json:"page_id"`
+	Title      string               `json:"title"`
+	URL        string               `json:"url"`
+	Time       time.Time            `json:"time"`
+	Diff       string               `json:"diff,omitempty"`
+	Properties []cli.PropertyChange `json:"properties,omitempty"`
+	Content    string               `json:"content"`
+}
+
+func runPageWatch(ctx *Context, page string, interval time.Duration, execCmd string, contextLines int) error {
+	if interval < time.Second {
+		return &output.UserError{Message: "--interval must be at least 1s"}
+	}
+
+	client, err := cli.RequireClient()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	// Stop cleanly on Ctrl-C or SIGTERM, between checks or mid-fetch.
+	watchCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pageID, err := cli.ResolvePageID(watchCtx, client, page)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+
+	last, err := client.Fetch(watchCtx, pageID)
+	if err != nil {
+		output.PrintError(err)
+		return err
+	}
+	if !ctx.JSON {
+		output.PrintInfo(fmt.Sprintf("Watching %s every %s (Ctrl-C to stop)", diffLabel(last, page), interval))
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-watchCtx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := client.Fetch(watchCtx, pageID)
+		if err != nil {
+			if watchCtx.Err() != nil {
+				return nil
+			}
+			output.PrintWarning(time.Now().Format("15:04:05") + " fetch failed, will retry: " + err.Error())
+			continue
+		}
+
+		change := comparePages(pageID, last, current, contextLines)
+		if change == nil {
+			continue
+		}
+		last = current
+
+		if err := printPageChange(change, ctx.JSON); err != nil {
+			return err
+		}
+		if execCmd != "" {
+			runWatchHook(execCmd, change)
+		}
+	}
+}
+
+// comparePages returns the change between two fetches of a page, or nil if
+// neither the content nor the properties changed.
+func comparePages(pageID string, old, new *mcp.FetchResult, contextLines int) *pageChange {
+	oldBody := cli.NormalizeMarkdown(old.Body())
+	newBody := cli.NormalizeMarkdown(new.Body())
+	diff := cli.UnifiedDiff("before", "after", oldBody, newBody, contextLines)
+	props := cli.PropertyChanges(old.Properties(), new.Properties())
+	if diff == "" && len(props) == 0 {
+		return nil
+	}
+	return &pageChange{
+		PageID:     pageID,
+		Title:      new.Title,
+		URL:        new.URL,
+		Time:       time.Now(),
+		Diff:       diff,
+		Properties: props,
+		Content:    new.Body(),
+	}
+}
+
+func printPageChange(change *pageChange, asJSON bool) error {
+	if asJSON {
+		data, err := json.Marshal(change)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	headerStyle := color.New(color.Bold)
+	_, _ = headerStyle.Printf("[%s] %s changed\n", change.Time.Format("2006-01-02 15:04:05"), change.Title)
+	for _, p := range change.Properties {
+		fmt.Printf("  %s: %s → %s\n", p.Name, orNone(p.Old), orNone(p.New))
+	}
+	if change.Diff != "" {
+		output.PrintDiff(change.Diff)
+	}
+	fmt.Println()
+	return nil
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// runWatchHook runs the --exec command with the change as JSON on stdin.
+// Failures are reported but don't stop the watch.
+func runWatchHook(command string, change *pageChange) {
+	data, err := json.Marshal(change)
+	if err != nil {
+		output.PrintWarning("--exec: " + err.Error())
+		return
+	}
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		output.PrintWarning("--exec command failed: " + err.Error())
+	}
+}
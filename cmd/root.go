@@ -6,7 +6,8 @@ type Context struct {
 }
 
 type CLI struct {
-	Token string `help:"Access token (skips OAuth)" env:"NOTION_ACCESS_TOKEN" hidden:""`
+	Token  string `help:"Access token (skips OAuth)" env:"NOTION_ACCESS_TOKEN" hidden:""`
+	DryRun bool   `help:"Print the tool calls that would change Notion, and skip local file writes, without doing either" name:"dry-run"`
 
 	Auth    AuthCmd    `cmd:"" help:"Authentication commands"`
 	Page    PageCmd    `cmd:"" help:"Page commands"`
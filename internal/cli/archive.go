@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lox/notion-cli/internal/mcp"
+)
+
+// SetArchived moves a page to the trash, or restores it, and fetches it again
+// to check that it happened. notion-update-page doesn't document archiving,
+// so a server that ignores the request is reported instead of taken as done.
+func SetArchived(ctx context.Context, client *mcp.Client, pageID string, archived bool) error {
+	if err := client.SetArchived(ctx, pageID, archived); err != nil {
+		return err
+	}
+	if client.DryRun() {
+		return nil
+	}
+
+	page, err := client.Fetch(ctx, pageID)
+	if err != nil {
+		return fmt.Errorf("check the page's trash status: %w", err)
+	}
+	if page.Archived != archived {
+		if archived {
+			return errors.New("the Notion MCP server didn't move the page to the trash; trash it in Notion instead")
+		}
+		return errors.New("the Notion MCP server didn't restore the page from the trash; restore it in Notion instead")
+	}
+	return nil
+}
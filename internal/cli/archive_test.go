@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/lox/notion-cli/internal/mcp"
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// fakeTrashServer serves notion-update-page and notion-fetch for one page,
+// applying the archived argument only when honour is set.
+func fakeTrashServer(t *testing.T, honour bool) *mcp.Client {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	inTrash := false
+	s := server.NewMCPServer("fake-notion", "test")
+	s.AddTool(mcpgo.NewTool("notion-update-page"), func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		if archived, ok := req.GetArguments()["archived"].(bool); ok && honour {
+			inTrash = archived
+		}
+		return mcpgo.NewToolResultText("{}"), nil
+	})
+	s.AddTool(mcpgo.NewTool("notion-fetch"), func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
+		resp := map[string]any{
+			"title":    "Page",
+			"url":      "https://www.notion.so/" + req.GetString("id", ""),
+			"text":     "<page>Body</page>",
+			"metadata": map[string]any{"type": "page", "in_trash": inTrash},
+		}
+		data, err := json.Marshal(resp)
+		if err != nil {
+			return nil, err
+		}
+		return mcpgo.NewToolResultText(string(data)), nil
+	})
+
+	ts := server.NewTestStreamableHTTPServer(s)
+	t.Cleanup(ts.Close)
+
+	client, err := mcp.NewClient(mcp.WithEndpoint(ts.URL+"/mcp"), mcp.WithAccessToken("test"))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestSetArchived(t *testing.T) {
+	const pageID = "1234567890abcdef1234567890abcdef"
+	ctx := context.Background()
+
+	client := fakeTrashServer(t, true)
+	if err := SetArchived(ctx, client, pageID, true); err != nil {
+		t.Errorf("SetArchived(true) error = %v", err)
+	}
+	if err := SetArchived(ctx, client, pageID, false); err != nil {
+		t.Errorf("SetArchived(false) error = %v", err)
+	}
+}
+
+func TestSetArchivedIgnored(t *testing.T) {
+	const pageID = "1234567890abcdef1234567890abcdef"
+
+	client := fakeTrashServer(t, false)
+	if err := SetArchived(context.Background(), client, pageID, true); err == nil {
+		t.Error("SetArchived() succeeded although the server ignored the archived argument")
+	}
+}
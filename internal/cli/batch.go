@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// BatchItem is one page to create, read from a line of JSONL.
+type BatchItem struct {
+	Title      string         `json:"title"`
+	Parent     string         `json:"parent,omitempty"`
+	ParentDB   string         `json:"parent_db,omitempty"`
+	Properties map[string]any `json:"properties,omitempty"`
+	Content    string         `json:"content,omitempty"`
+	Icon       string         `json:"icon,omitempty"`
+	Cover      string         `json:"cover,omitempty"`
+
+	// Line is the item's line number in the input and Raw its original
+	// JSON, so failed items can be reported and re-run as they were given.
+	Line int             `json:"-"`
+	Raw  json.RawMessage `json:"-"`
+	// Err is set when the line couldn't be parsed.
+	Err error `json:"-"`
+}
+
+// ReadBatchItems reads JSONL, one page per line. Blank lines are skipped.
+// Lines that aren't valid items are returned with Err set rather than
+// stopping the batch.
+func ReadBatchItems(r io.Reader) ([]BatchItem, error) {
+	var items []BatchItem
+	reader := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("read batch: %w", err)
+		}
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			item := BatchItem{Line: n, Raw: json.RawMessage(trimmed)}
+			if jsonErr := json.Unmarshal([]byte(trimmed), &item); jsonErr != nil {
+				item.Err = fmt.Errorf("invalid JSON: %w", jsonErr)
+				item.Raw = nil
+			} else if strings.TrimSpace(item.Title) == "" {
+				item.Err = fmt.Errorf("title is required")
+			}
+			items = append(items, item)
+		}
+		if err == io.EOF {
+			return items, nil
+		}
+	}
+}
+
+// RawProperties flattens the item's property values into the strings
+// CoerceProperties accepts.
+func (b BatchItem) RawProperties() (map[string]string, error) {
+	raw := make(map[string]string, len(b.Properties))
+	for k, v := range b.Properties {
+		s, ok := PropertyString(v)
+		if !ok {
+			return nil, fmt.Errorf("unsupported value for property %q", k)
+		}
+		raw[k] = s
+	}
+	return raw, nil
+}
+
+// GroupBatch splits items, given their sizes in bytes, into consecutive
+// groups of at most maxItems items and maxBytes bytes. An item larger than
+// maxBytes gets a group of its own.
+func GroupBatch(sizes []int, maxItems, maxBytes int) [][]int {
+	var groups [][]int
+	var group []int
+	total := 0
+	for i, size := range sizes {
+		if len(group) > 0 && (len(group) >= maxItems || total+size > maxBytes) {
+			groups = append(groups, group)
+			group, total = nil, 0
+		}
+		group = append(group, i)
+		total += size
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+	return groups
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadBatchItems(t *testing.T) {
+	input := `{"title": "One", "parent": "Engineering", "properties": {"Tags": ["a", "b"], "Points": 3}}
+
+{"title": "Two", "content": "# Body"}
+not json
+{"content": "no title"}`
+
+	items, err := ReadBatchItems(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 4 {
+		t.Fatalf("got %d items, want 4", len(items))
+	}
+
+	if items[0].Title != "One" || items[0].Parent != "Engineering" || items[0].Line != 1 || items[0].Err != nil {
+		t.Errorf("item 0 = %+v", items[0])
+	}
+	raw, err := items[0].RawProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"Tags": "a, b", "Points": "3"}; !reflect.DeepEqual(raw, want) {
+		t.Errorf("RawProperties() = %v, want %v", raw, want)
+	}
+
+	if items[1].Title != "Two" || items[1].Line != 3 || items[1].Content != "# Body" {
+		t.Errorf("item 1 = %+v", items[1])
+	}
+	if items[2].Err == nil || items[2].Line != 4 {
+		t.Errorf("item 2 = %+v, want a parse error", items[2])
+	}
+	if items[3].Err == nil || string(items[3].Raw) != `{"content": "no title"}` {
+		t.Errorf("item 3 = %+v, want a missing title error", items[3])
+	}
+}
+
+func TestGroupBatch(t *testing.T) {
+	got := GroupBatch([]int{10, 10, 10, 50, 10, 10}, 3, 40)
+	want := [][]int{{0, 1, 2}, {3}, {4, 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupBatch() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,359 @@
+package cli
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+// MaxChunkSize is the most Markdown, in bytes, sent to Notion in one call.
+// Larger bodies are split and sent in order.
+const MaxChunkSize = 40 * 1024
+
+// blockTagRe matches the opening and closing lines of Notion's multi-line
+// blocks, such as <table> and <callout>, which must not be split.
+var blockTagRe = regexp.MustCompile(`^\s*<(/?)(table|callout|details|columns|column|synced_block)\b[^>]*?(/?)>`)
+
+// SplitBlocks splits Markdown into chunks of at most max bytes, breaking only
+// between blocks: never inside a code fence, a $$ equation, a multi-line tag
+// block, a table or a paragraph, nor before an indented child line. A single
+// block larger than max becomes a chunk of its own. Joining the chunks with
+// "\n" gives back the input.
+func SplitBlocks(markdown string, max int) []string {
+	if len(markdown) <= max {
+		return []string{markdown}
+	}
+
+	lines := strings.Split(markdown, "\n")
+	var units []string
+	start := 0
+	inFence, inMath := false, false
+	depth := 0
+	for i, line := range lines {
+		if i > start && !inFence && !inMath && depth == 0 && canBreakBefore(lines[i-1], line) {
+			units = append(units, strings.Join(lines[start:i], "\n"))
+			start = i
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case codeFenceLine.MatchString(line):
+			inFence = !inFence
+		case inFence:
+		case trimmed == "$$":
+			inMath = !inMath
+		default:
+			if m := blockTagRe.FindStringSubmatch(line); m != nil && m[3] == "" {
+				if m[1] == "/" {
+					depth--
+				} else if !strings.Contains(line, "</"+m[2]+">") {
+					depth++
+				}
+			}
+		}
+	}
+	units = append(units, strings.Join(lines[start:], "\n"))
+
+	var chunks, chunk []string
+	size := 0
+	for _, u := range units {
+		if len(chunk) > 0 && size+1+len(u) > max {
+			chunks = append(chunks, strings.Join(chunk, "\n"))
+			chunk, size = nil, 0
+		}
+		if len(chunk) > 0 {
+			size++
+		}
+		chunk = append(chunk, u)
+		size += len(u)
+	}
+	return append(chunks, strings.Join(chunk, "\n"))
+}
+
+var blockStartRe = regexp.MustCompile(`^(#{1,6} |[-*+] |\d+[.)] |> |<|` + "```" + `|~~~|\$\$|---)`)
+
+// canBreakBefore reports whether a chunk can start at line, given the line
+// before it.
+func canBreakBefore(prev, line string) bool {
+	if strings.TrimSpace(line) == "" || line[0] == ' ' || line[0] == '\t' {
+		return false
+	}
+	if strings.HasPrefix(line, "|") && strings.HasPrefix(prev, "|") {
+		return false
+	}
+	return strings.TrimSpace(prev) == "" || blockStartRe.MatchString(line)
+}
+
+// uploadState records how far a chunked upload got, so running the same
+// command again carries on from there. A state without a PageID is a create
+// that was started but not confirmed.
+type uploadState struct {
+	PageID string `json:"page_id"`
+	URL    string `json:"url,omitempty"`
+	Done   int    `json:"done"`
+	Total  int    `json:"total"`
+}
+
+func uploadStatePath(key string) (string, error) {
+	dir, err := CacheDir("uploads")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, key+".json"), nil
+}
+
+// uploadKey identifies an upload by what it sends where.
+func uploadKey(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+func loadUploadState(key string) *uploadState {
+	path, err := uploadStatePath(key)
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var state uploadState
+	if err := json.Unmarshal(data, &state); err != nil || state.Total == 0 {
+		return nil
+	}
+	return &state
+}
+
+func saveUploadState(key string, state *uploadState) {
+	if DryRun() {
+		return
+	}
+	path, err := uploadStatePath(key)
+	if err == nil {
+		var data []byte
+		if data, err = json.Marshal(state); err == nil {
+			err = os.WriteFile(path, data, 0o600)
+		}
+	}
+	if err != nil {
+		output.PrintWarning("Could not save upload progress: " + err.Error())
+	}
+}
+
+func clearUploadState(key string) {
+	if DryRun() {
+		return
+	}
+	if path, err := uploadStatePath(key); err == nil {
+		_ = os.Remove(path)
+	}
+}
+
+// CreatePage creates a page like client.CreatePage, sending content larger
+// than MaxChunkSize in chunks: the page is created with the first and the
+// rest are appended in order. If an upload stops part way, running it again
+// with the same page and content resumes it.
+func CreatePage(ctx context.Context, client *mcp.Client, req mcp.CreatePageRequest) (*mcp.CreatePageResponse, error) {
+	chunks := SplitBlocks(req.Content, MaxChunkSize)
+	if len(chunks) == 1 {
+		return client.CreatePage(ctx, req)
+	}
+
+	key := uploadKey("create", req.ParentPageID, req.ParentDatabaseID, req.Title, req.Content)
+	state := loadUploadState(key)
+	resuming := state != nil && state.Total == len(chunks)
+	if resuming && state.PageID == "" {
+		// The last attempt stopped around the create itself, so the page
+		// may or may not exist. Look for it rather than risk a duplicate.
+		existing, err := findStartedPage(ctx, client, req, chunks[0])
+		if err != nil {
+			return nil, fmt.Errorf("resuming upload of %s: %w", req.Title, err)
+		}
+		if existing == nil {
+			resuming = false
+		} else {
+			state = &uploadState{PageID: existing.ID, URL: existing.URL, Done: 1, Total: len(chunks)}
+			saveUploadState(key, state)
+		}
+	}
+	if resuming {
+		output.PrintProgress(fmt.Sprintf("%s: resuming upload (%d of %d parts already sent)", req.Title, state.Done, state.Total))
+	} else {
+		// Record the create as pending first, so that if it fails without
+		// an answer the next run looks for the page before creating another.
+		saveUploadState(key, &uploadState{Total: len(chunks)})
+
+		first := req
+		first.Content = chunks[0]
+		resp, err := client.CreatePage(ctx, first)
+		if err != nil {
+			// Start afresh next time rather than adopt a page this create
+			// may never have made.
+			clearUploadState(key)
+			return nil, err
+		}
+		pageID := resp.ID
+		if pageID == "" {
+			pageID, _ = ExtractNotionUUID(resp.URL)
+		}
+		if pageID == "" && !client.DryRun() {
+			return nil, errors.New("page created but its ID is unknown, so the rest of the content was not uploaded")
+		}
+		state = &uploadState{PageID: pageID, URL: resp.URL, Done: 1, Total: len(chunks)}
+		saveUploadState(key, state)
+		output.PrintProgress(fmt.Sprintf("%s: uploaded part 1 of %d", req.Title, len(chunks)))
+	}
+
+	if err := appendChunks(ctx, client, key, state, chunks, req.Title, resuming); err != nil {
+		return nil, err
+	}
+	return &mcp.CreatePageResponse{ID: state.PageID, URL: state.URL}, nil
+}
+
+// findStartedPage looks for the page an interrupted create made: a page with
+// req's title under its parent whose content begins with the first chunk.
+// When several do, it can't tell which, so it warns and returns none.
+func findStartedPage(ctx context.Context, client *mcp.Client, req mcp.CreatePageRequest, first string) (*ExistingPage, error) {
+	candidates, err := FindExistingPages(ctx, client, req, "")
+	if err != nil {
+		return nil, err
+	}
+	var started []ExistingPage
+	for _, c := range candidates {
+		page, err := client.Fetch(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+		if startsWithChunk(page.Body(), first) {
+			started = append(started, c)
+		}
+	}
+	switch len(started) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &started[0], nil
+	}
+	output.PrintWarning(fmt.Sprintf("%s: %d pages could be the interrupted upload; starting again", req.Title, len(started)))
+	return nil, nil
+}
+
+// ReplaceContent replaces a page's content, sending content larger than
+// MaxChunkSize in chunks the same way as CreatePage.
+func ReplaceContent(ctx context.Context, client *mcp.Client, pageID, content string) error {
+	chunks := SplitBlocks(content, MaxChunkSize)
+	req := mcp.UpdatePageRequest{
+		PageID:     pageID,
+		Command:    "replace_content",
+		NewContent: chunks[0],
+	}
+	if len(chunks) == 1 {
+		return client.UpdatePage(ctx, req)
+	}
+
+	key := uploadKey("replace", pageID, content)
+	state := loadUploadState(key)
+	resuming := state != nil && state.Total == len(chunks)
+	if resuming {
+		output.PrintProgress(fmt.Sprintf("%s: resuming upload (%d of %d parts already sent)", pageID, state.Done, state.Total))
+	} else {
+		if err := client.UpdatePage(ctx, req); err != nil {
+			return err
+		}
+		state = &uploadState{PageID: pageID, Done: 1, Total: len(chunks)}
+		saveUploadState(key, state)
+		output.PrintProgress(fmt.Sprintf("%s: uploaded part 1 of %d", pageID, len(chunks)))
+	}
+
+	return appendChunks(ctx, client, key, state, chunks, pageID, resuming)
+}
+
+// appendChunks appends the chunks state hasn't recorded as sent, anchoring
+// each after the end of the page as fetched. When resuming, a chunk that the
+// page already ends with, because the last attempt failed after Notion
+// applied it, is not sent again.
+func appendChunks(ctx context.Context, client *mcp.Client, key string, state *uploadState, chunks []string, label string, resuming bool) error {
+	// In a dry run there is no page to fetch, so anchor on what would
+	// have been sent so far.
+	sent := strings.Join(chunks[:state.Done], "\n")
+
+	for i := state.Done; i < len(chunks); i++ {
+		body := sent
+		if !client.DryRun() {
+			fetched, err := client.Fetch(ctx, state.PageID)
+			if err != nil {
+				return uploadStopped(state, err)
+			}
+			body = fetched.Body()
+		}
+
+		chunk := strings.Trim(chunks[i], "\n")
+		if resuming && endsWithChunk(body, chunk) {
+			output.PrintProgress(fmt.Sprintf("%s: part %d of %d was already uploaded", label, i+1, len(chunks)))
+		} else if err := appendChunk(ctx, client, state.PageID, body, chunk); err != nil {
+			return uploadStopped(state, err)
+		}
+		resuming = false
+
+		sent += "\n" + chunks[i]
+		state.Done = i + 1
+		saveUploadState(key, state)
+		output.PrintProgress(fmt.Sprintf("%s: uploaded part %d of %d", label, i+1, len(chunks)))
+	}
+
+	clearUploadState(key)
+	return nil
+}
+
+func appendChunk(ctx context.Context, client *mcp.Client, pageID, body, chunk string) error {
+	edit, ok := PlanAppend(body, chunk)
+	if !ok {
+		return errors.New("could not find the end of the page to append to")
+	}
+	return client.UpdatePage(ctx, mcp.UpdatePageRequest{
+		PageID:    pageID,
+		Command:   "insert_content_after",
+		Selection: edit.Selection,
+		NewStr:    edit.NewStr,
+	})
+}
+
+func uploadStopped(state *uploadState, err error) error {
+	return fmt.Errorf("upload stopped after %d of %d parts (run the same command again to resume): %w", state.Done, state.Total, err)
+}
+
+// startsWithChunk reports whether the page body begins with the whole of
+// chunk, comparing normalised Markdown.
+func startsWithChunk(body, chunk string) bool {
+	want := strings.Trim(NormalizeMarkdown(chunk), "\n")
+	if want == "" {
+		return false
+	}
+	have := strings.TrimLeft(NormalizeMarkdown(body), "\n")
+	return have == want || strings.HasPrefix(have, want+"\n")
+}
+
+// endsWithChunk reports whether the page body ends with the whole of chunk,
+// comparing normalised Markdown.
+func endsWithChunk(body, chunk string) bool {
+	want := strings.Trim(NormalizeMarkdown(chunk), "\n")
+	if want == "" {
+		return false
+	}
+	have := strings.TrimRight(NormalizeMarkdown(body), "\n")
+	return have == want || strings.HasSuffix(have, "\n"+want)
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitBlocks(t *testing.T) {
+	para := strings.Repeat("word ", 9) + "\n" + strings.Repeat("more ", 9)
+	fence := "```go\n" + strings.Repeat("x := 1\n\ny := 2\n", 10) + "```"
+	table := "<table>\n<tr>\n<td>a</td>\n</tr>\n\n<tr>\n<td>b</td>\n</tr>\n</table>"
+	mdTable := "| a | b |\n| - | - |\n| 1 | 2 |\n| 3 | 4 |"
+	list := "- one\n\t- child\n- two"
+	doc := strings.Join([]string{"# Title", "", para, "", fence, "", table, "", mdTable, "", list, "", para}, "\n")
+
+	chunks := SplitBlocks(doc, 100)
+	if len(chunks) < 4 {
+		t.Fatalf("SplitBlocks() made %d chunks, want several", len(chunks))
+	}
+	if got := strings.Join(chunks, "\n"); got != doc {
+		t.Fatalf("joined chunks differ from input:\n%q\nwant\n%q", got, doc)
+	}
+
+	for _, c := range chunks {
+		if strings.Count(c, "```")%2 != 0 {
+			t.Errorf("chunk splits a code fence: %q", c)
+		}
+		if strings.Count(c, "<table>") != strings.Count(c, "</table>") {
+			t.Errorf("chunk splits a table: %q", c)
+		}
+		if strings.HasPrefix(c, "\t") || strings.HasPrefix(c, "more") || strings.HasPrefix(c, "| 3") {
+			t.Errorf("chunk starts mid-block: %q", c)
+		}
+		if len(c) > 100 && c != fence && !strings.HasPrefix(strings.TrimSpace(c), "```") {
+			t.Errorf("chunk of %d bytes is over the limit: %q", len(c), c)
+		}
+	}
+
+	if got := SplitBlocks("short", 100); len(got) != 1 || got[0] != "short" {
+		t.Errorf("SplitBlocks() of short input = %q", got)
+	}
+}
+
+func TestStartsWithChunk(t *testing.T) {
+	body := "# Doc\n\n* first\n* second\n\nMore text\n"
+	if !startsWithChunk(body, "# Doc\n\n- first\n- second") {
+		t.Error("startsWithChunk() = false for the page's first chunk")
+	}
+	if startsWithChunk(body, "# Doc\n\n- other") {
+		t.Error("startsWithChunk() = true for a page that only shares the title")
+	}
+	if startsWithChunk(body, "# Do") {
+		t.Error("startsWithChunk() = true for a partial line")
+	}
+}
+
+func TestEndsWithChunk(t *testing.T) {
+	body := "# Doc\n\n* first\n* last item  \n"
+	if !endsWithChunk(body, "- first\n- last item") {
+		t.Error("endsWithChunk() = false for an applied chunk")
+	}
+	if endsWithChunk(body, "- next") {
+		t.Error("endsWithChunk() = true for a chunk that wasn't applied")
+	}
+	if endsWithChunk(body, "- other\n- last item") {
+		t.Error("endsWithChunk() = true for a chunk that only shares its last line")
+	}
+}
@@ -3,18 +3,34 @@ package cli
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/lox/notion-cli/internal/mcp"
 	"github.com/lox/notion-cli/internal/output"
 )
 
-var accessToken string
+var (
+	accessToken string
+	dryRun      bool
+)
 
 func SetAccessToken(token string) {
 	accessToken = token
 }
 
+// SetDryRun makes clients print the changes they would make instead of
+// making them, and tells commands to skip writing local files.
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+	output.SetDryRun(enabled)
+}
+
+// DryRun reports whether --dry-run is in effect.
+func DryRun() bool {
+	return dryRun
+}
+
 func GetClient() (*mcp.Client, error) {
 	ctx := context.Background()
 
@@ -30,6 +46,9 @@ func GetClient() (*mcp.Client, error) {
 	if accessToken != "" {
 		opts = append(opts, mcp.WithAccessToken(accessToken))
 	}
+	if dryRun {
+		opts = append(opts, mcp.WithDryRun(os.Stderr))
+	}
 
 	client, err := mcp.NewClient(opts...)
 	if err != nil {
@@ -0,0 +1,281 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+type DiffOpKind int
+
+const (
+	DiffEqual DiffOpKind = iota
+	DiffDelete
+	DiffInsert
+)
+
+// DiffOp is a single line in a line diff.
+type DiffOp struct {
+	Kind DiffOpKind
+	Line string
+}
+
+// Hunk is a run of changed lines. OldStart and NewStart are zero-based line
+// indexes into the old and new inputs.
+type Hunk struct {
+	OldStart int
+	OldLines []string
+	NewStart int
+	NewLines []string
+}
+
+// SplitLines splits text into lines, ignoring a single trailing newline.
+func SplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// DiffLines computes a minimal line diff between a and b using Myers'
+// algorithm in its linear-space form, which splits the problem at the middle
+// of an optimal path instead of keeping every step, so memory stays
+// proportional to the input.
+func DiffLines(a, b []string) []DiffOp {
+	if len(a)+len(b) == 0 {
+		return nil
+	}
+	size := (len(a)+len(b)+1)/2 + 1
+	d := &differ{a: a, b: b, off: size, vf: make([]int, 2*size+1), vb: make([]int, 2*size+1)}
+	d.diff(0, len(a), 0, len(b))
+	return d.ops
+}
+
+// differ holds the inputs, output and working space of DiffLines.
+type differ struct {
+	a, b   []string
+	ops    []DiffOp
+	off    int   // index of diagonal 0 in vf and vb
+	vf, vb []int // furthest x reached on each diagonal, forwards and backwards
+}
+
+// diff appends the ops turning a[a0:a1] into b[b0:b1].
+func (d *differ) diff(a0, a1, b0, b1 int) {
+	for a0 < a1 && b0 < b1 && d.a[a0] == d.b[b0] {
+		d.ops = append(d.ops, DiffOp{Kind: DiffEqual, Line: d.a[a0]})
+		a0++
+		b0++
+	}
+	suffix := 0
+	for a1-suffix > a0 && b1-suffix > b0 && d.a[a1-suffix-1] == d.b[b1-suffix-1] {
+		suffix++
+	}
+	a1, b1 = a1-suffix, b1-suffix
+
+	switch {
+	case a0 == a1:
+		for _, line := range d.b[b0:b1] {
+			d.ops = append(d.ops, DiffOp{Kind: DiffInsert, Line: line})
+		}
+	case b0 == b1:
+		for _, line := range d.a[a0:a1] {
+			d.ops = append(d.ops, DiffOp{Kind: DiffDelete, Line: line})
+		}
+	default:
+		// With the common ends gone both sides differ, so at least two
+		// edits remain and each half below needs fewer.
+		x, y, u, v := d.middleSnake(a0, a1, b0, b1)
+		d.diff(a0, x, b0, y)
+		for _, line := range d.a[x:u] {
+			d.ops = append(d.ops, DiffOp{Kind: DiffEqual, Line: line})
+		}
+		d.diff(u, a1, v, b1)
+	}
+
+	for _, line := range d.a[a1 : a1+suffix] {
+		d.ops = append(d.ops, DiffOp{Kind: DiffEqual, Line: line})
+	}
+}
+
+// middleSnake finds the run of matching lines, from (x, y) to (u, v), in the
+// middle of an optimal path through a[a0:a1] and b[b0:b1], by searching from
+// both ends until the two searches meet.
+func (d *differ) middleSnake(a0, a1, b0, b1 int) (x, y, u, v int) {
+	n, m := a1-a0, b1-b0
+	delta := n - m
+	odd := delta%2 != 0
+	vf, vb, off := d.vf, d.vb, d.off
+	vf[off+1], vb[off+1] = 0, 0
+
+	for D := 0; D <= (n+m+1)/2; D++ {
+		for k := -D; k <= D; k += 2 {
+			var fx int
+			if k == -D || (k != D && vf[off+k-1] < vf[off+k+1]) {
+				fx = vf[off+k+1]
+			} else {
+				fx = vf[off+k-1] + 1
+			}
+			fy := fx - k
+			sx, sy := fx, fy
+			for fx < n && fy < m && d.a[a0+fx] == d.b[b0+fy] {
+				fx++
+				fy++
+			}
+			vf[off+k] = fx
+			// Backward diagonal c is forward diagonal delta-c.
+			if c := delta - k; odd && c >= -(D-1) && c <= D-1 && fx+vb[off+c] >= n {
+				return a0 + sx, b0 + sy, a0 + fx, b0 + fy
+			}
+		}
+
+		for c := -D; c <= D; c += 2 {
+			var bx int
+			if c == -D || (c != D && vb[off+c-1] < vb[off+c+1]) {
+				bx = vb[off+c+1]
+			} else {
+				bx = vb[off+c-1] + 1
+			}
+			by := bx - c
+			sx, sy := bx, by
+			for bx < n && by < m && d.a[a1-bx-1] == d.b[b1-by-1] {
+				bx++
+				by++
+			}
+			vb[off+c] = bx
+			if k := delta - c; !odd && k >= -D && k <= D && vf[off+k]+bx >= n {
+				return a1 - bx, b1 - by, a1 - sx, b1 - sy
+			}
+		}
+	}
+	panic("cli: diff found no middle snake")
+}
+
+// Hunks groups a diff into runs of changed lines.
+func Hunks(ops []DiffOp) []Hunk {
+	var hunks []Hunk
+	var cur *Hunk
+	oldIdx, newIdx := 0, 0
+
+	for _, op := range ops {
+		switch op.Kind {
+		case DiffEqual:
+			if cur != nil {
+				hunks = append(hunks, *cur)
+				cur = nil
+			}
+			oldIdx++
+			newIdx++
+		case DiffDelete:
+			if cur == nil {
+				cur = &Hunk{OldStart: oldIdx, NewStart: newIdx}
+			}
+			cur.OldLines = append(cur.OldLines, op.Line)
+			oldIdx++
+		case DiffInsert:
+			if cur == nil {
+				cur = &Hunk{OldStart: oldIdx, NewStart: newIdx}
+			}
+			cur.NewLines = append(cur.NewLines, op.Line)
+			newIdx++
+		}
+	}
+	if cur != nil {
+		hunks = append(hunks, *cur)
+	}
+	return hunks
+}
+
+// UnifiedDiff renders a unified diff of two texts with the given number of
+// context lines. It returns an empty string when the texts are equal.
+func UnifiedDiff(oldName, newName, oldText, newText string, context int) string {
+	ops := DiffLines(SplitLines(oldText), SplitLines(newText))
+
+	changed := false
+	for _, op := range ops {
+		if op.Kind != DiffEqual {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+
+	// Walk the ops, emitting groups of changes with surrounding context.
+	i := 0
+	oldLine, newLine := 0, 0
+	for i < len(ops) {
+		if ops[i].Kind == DiffEqual {
+			i++
+			oldLine++
+			newLine++
+			continue
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(ops) {
+			if ops[end].Kind != DiffEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].Kind == DiffEqual {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end += min(context, run-end)
+				break
+			}
+			end = run
+		}
+
+		hunkOld := oldLine - (i - start)
+		hunkNew := newLine - (i - start)
+		var oldCount, newCount int
+		var body strings.Builder
+		for _, op := range ops[start:end] {
+			switch op.Kind {
+			case DiffEqual:
+				body.WriteString(" " + op.Line + "\n")
+				oldCount++
+				newCount++
+			case DiffDelete:
+				body.WriteString("-" + op.Line + "\n")
+				oldCount++
+			case DiffInsert:
+				body.WriteString("+" + op.Line + "\n")
+				newCount++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(hunkOld, oldCount), hunkRange(hunkNew, newCount))
+		b.WriteString(body.String())
+
+		for _, op := range ops[i:end] {
+			if op.Kind != DiffInsert {
+				oldLine++
+			}
+			if op.Kind != DiffDelete {
+				newLine++
+			}
+		}
+		i = end
+	}
+
+	return b.String()
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	a := []string{"a", "b", "c", "d"}
+	b := []string{"a", "c", "d", "e"}
+	ops := DiffLines(a, b)
+
+	var got []string
+	for _, op := range ops {
+		switch op.Kind {
+		case DiffEqual:
+			got = append(got, " "+op.Line)
+		case DiffDelete:
+			got = append(got, "-"+op.Line)
+		case DiffInsert:
+			got = append(got, "+"+op.Line)
+		}
+	}
+	want := " a\n-b\n c\n d\n+e"
+	if strings.Join(got, "\n") != want {
+		t.Errorf("DiffLines() =\n%s\nwant\n%s", strings.Join(got, "\n"), want)
+	}
+}
+
+func TestDiffLinesMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, rng.Intn(12))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return lines
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := randomLines(), randomLines()
+		ops := DiffLines(a, b)
+
+		var gotA, gotB []string
+		edits := 0
+		for _, op := range ops {
+			if op.Kind != DiffInsert {
+				gotA = append(gotA, op.Line)
+			}
+			if op.Kind != DiffDelete {
+				gotB = append(gotB, op.Line)
+			}
+			if op.Kind != DiffEqual {
+				edits++
+			}
+		}
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("DiffLines(%q, %q) = %v doesn't rebuild the inputs", a, b, ops)
+		}
+		if want := len(a) + len(b) - 2*lcsLength(a, b); edits != want {
+			t.Fatalf("DiffLines(%q, %q) made %d edits, want %d", a, b, edits, want)
+		}
+	}
+}
+
+func TestDiffLinesLarge(t *testing.T) {
+	a := make([]string, 5000)
+	b := make([]string, 5000)
+	for i := range a {
+		a[i] = fmt.Sprintf("old %d", i)
+		b[i] = fmt.Sprintf("new %d", i)
+	}
+	if ops := DiffLines(a, b); len(ops) != len(a)+len(b) {
+		t.Errorf("DiffLines() returned %d ops, want %d", len(ops), len(a)+len(b))
+	}
+}
+
+func lcsLength(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else {
+				dp[i][j] = max(dp[i+1][j], dp[i][j+1])
+			}
+		}
+	}
+	return dp[0][0]
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	oldText := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	newText := "one\ntwo\nTHREE\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+
+	got := UnifiedDiff("a.md", "b.md", oldText, newText, 1)
+	want := `--- a.md
++++ b.md
+@@ -2,3 +2,3 @@
+ two
+-three
++THREE
+ four
+@@ -10 +10,2 @@
+ ten
++eleven
+`
+	if got != want {
+		t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := UnifiedDiff("a", "b", oldText, oldText, 3); got != "" {
+		t.Errorf("UnifiedDiff() of equal texts = %q, want empty", got)
+	}
+}
+
+// applyEdits simulates the server applying replace_content_range edits.
+func applyEdits(t *testing.T, doc string, edits []ContentEdit) string {
+	t.Helper()
+	for _, e := range edits {
+		start, end := -1, -1
+		if prefix, suffix, ok := strings.Cut(e.Selection, "..."); ok {
+			start = strings.Index(doc, prefix)
+			if start >= 0 {
+				if idx := strings.Index(doc[start+len(prefix):], suffix); idx >= 0 {
+					end = start + len(prefix) + idx + len(suffix)
+				}
+			}
+		} else {
+			start = strings.Index(doc, e.Selection)
+			end = start + len(e.Selection)
+		}
+		if start < 0 || end < 0 {
+			t.Fatalf("selection %q not found in %q", e.Selection, doc)
+		}
+		doc = doc[:start] + e.NewStr + doc[end:]
+	}
+	return doc
+}
+
+func TestPlanContentEdits(t *testing.T) {
+	long := strings.Repeat("This paragraph is long enough to need an ellipsis. ", 3)
+
+	tests := []struct {
+		name   string
+		old    string
+		new    string
+		wantOK bool
+	}{
+		{"no change", "# Title\n\nBody", "# Title\n\nBody", true},
+		{"replace line", "# Title\n\nOld body\n\nFooter", "# Title\n\nNew body\n\nFooter", true},
+		{"insert line", "# Title\n\nBody", "# Title\n\nBody\n\nMore", true},
+		{"insert at start", "Body\n\nFooter", "# Title\nBody\n\nFooter", true},
+		{"delete line", "# Title\n\nRemove me\n\nKeep", "# Title\n\nKeep", true},
+		{"multiple hunks", "a1\nb1\nc1\nd1\ne1", "a1\nB1\nc1\nD1\ne1", true},
+		{"long paragraph", "# T\n\n" + long + "\n\nEnd", "# T\n\n" + long + "changed\n\nEnd", true},
+		{"ambiguous duplicate line", "same\nx\nsame\ny\nsame", "same\nx\nother\ny\nsame", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits, ok := PlanContentEdits(tt.old, tt.new)
+			if ok != tt.wantOK {
+				t.Fatalf("PlanContentEdits() ok = %v, want %v (edits %q)", ok, tt.wantOK, edits)
+			}
+			if !ok {
+				return
+			}
+			if got := applyEdits(t, tt.old, edits); got != tt.new {
+				t.Errorf("applied edits = %q, want %q", got, tt.new)
+			}
+		})
+	}
+}
+
+func TestPlanAppend(t *testing.T) {
+	tests := []string{
+		"# Changelog\n\n- v1.0\n",
+		"- deploy\n- deploy\n- deploy",
+		"Intro\n\n" + strings.Repeat("Repeated paragraph that goes on for a while. ", 4) + "\n\n" + strings.Repeat("Repeated paragraph that goes on for a while. ", 4),
+	}
+	for _, doc := range tests {
+		edit, ok := PlanAppend(doc, "- v1.1")
+		if !ok {
+			t.Fatalf("PlanAppend(%q) not ok", doc)
+		}
+		// insert_content_after keeps the selection and adds NewStr after it.
+		got := applyEdits(t, doc, []ContentEdit{{Selection: edit.Selection, NewStr: selected(t, doc, edit.Selection) + edit.NewStr}})
+		if want := strings.TrimRight(doc, "\n") + "\n- v1.1" + doc[len(strings.TrimRight(doc, "\n")):]; got != want {
+			t.Errorf("PlanAppend(%q) gives %q, want %q", doc, got, want)
+		}
+	}
+
+	if _, ok := PlanAppend("\n\n", "x"); ok {
+		t.Error("PlanAppend() of an empty page should not be ok")
+	}
+}
+
+func TestPlanPrepend(t *testing.T) {
+	tests := []string{
+		"# Changelog\n\n- v1.0",
+		"\n- deploy\n- deploy\nend",
+	}
+	for _, doc := range tests {
+		edit, ok := PlanPrepend(doc, "- v1.1")
+		if !ok {
+			t.Fatalf("PlanPrepend(%q) not ok", doc)
+		}
+		got := applyEdits(t, doc, []ContentEdit{edit})
+		lead := len(doc) - len(strings.TrimLeft(doc, "\n"))
+		if want := doc[:lead] + "- v1.1\n" + doc[lead:]; got != want {
+			t.Errorf("PlanPrepend(%q) gives %q, want %q", doc, got, want)
+		}
+	}
+
+	if _, ok := PlanPrepend("", "x"); ok {
+		t.Error("PlanPrepend() of an empty page should not be ok")
+	}
+}
+
+// selected returns the text a selection_with_ellipsis matches in doc.
+func selected(t *testing.T, doc, selection string) string {
+	t.Helper()
+	if prefix, suffix, ok := strings.Cut(selection, "..."); ok {
+		start := strings.Index(doc, prefix)
+		end := start + len(prefix) + strings.Index(doc[start+len(prefix):], suffix) + len(suffix)
+		return doc[start:end]
+	}
+	return selection
+}
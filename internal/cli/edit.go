@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ContentEdit is a replace_content_range edit: the range of page content
+// matched by Selection (in selection_with_ellipsis form) becomes NewStr.
+type ContentEdit struct {
+	Selection string
+	NewStr    string
+}
+
+// maxTargetedEdits is the number of changed regions above which replacing the
+// whole page is simpler than a series of targeted edits.
+const maxTargetedEdits = 20
+
+// maxInlineSelection is the longest selection sent verbatim; longer ranges
+// are abbreviated to "start...end".
+const maxInlineSelection = 60
+
+// PlanContentEdits works out targeted edits that turn oldText into newText.
+// Edits must be applied in the order returned. It returns false when some
+// change can't be addressed by an unambiguous selection, in which case the
+// caller should fall back to replacing the whole content.
+func PlanContentEdits(oldText, newText string) ([]ContentEdit, bool) {
+	doc := SplitLines(oldText)
+	hunks := Hunks(DiffLines(doc, SplitLines(newText)))
+	if len(hunks) == 0 {
+		return nil, true
+	}
+	if len(hunks) > maxTargetedEdits {
+		return nil, false
+	}
+
+	// Apply hunks from last to first so earlier line numbers stay valid, and
+	// check each selection against the document as it will be at that point.
+	var edits []ContentEdit
+	for i := len(hunks) - 1; i >= 0; i-- {
+		h := hunks[i]
+		start, end := h.OldStart, h.OldStart+len(h.OldLines)
+		replacement := append([]string(nil), h.NewLines...)
+
+		// Pure insertions and deletions, or changes to blank lines, need a
+		// neighbouring line to anchor the selection.
+		for len(replacement) == 0 || strings.TrimSpace(strings.Join(doc[start:end], "\n")) == "" {
+			switch {
+			case start > 0:
+				start--
+				replacement = append([]string{doc[start]}, replacement...)
+			case end < len(doc):
+				replacement = append(replacement, doc[end])
+				end++
+			default:
+				return nil, false
+			}
+		}
+
+		text := strings.Join(doc, "\n")
+		startOff, endOff := lineOffsets(doc, start, end)
+		selection, ok := SelectionFor(text, startOff, endOff)
+		if !ok {
+			return nil, false
+		}
+		edits = append(edits, ContentEdit{Selection: selection, NewStr: strings.Join(replacement, "\n")})
+
+		updated := append([]string(nil), doc[:start]...)
+		updated = append(updated, replacement...)
+		doc = append(updated, doc[end:]...)
+	}
+
+	return edits, true
+}
+
+// PlanAppend works out an insert_content_after edit that adds content at the
+// end of doc: Selection matches the end of the document and NewStr is the
+// text to insert after it. It returns false when doc is empty, in which case
+// the caller should set the content instead.
+func PlanAppend(doc, content string) (ContentEdit, bool) {
+	doc = strings.TrimRight(doc, "\n")
+	lines := SplitLines(doc)
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) == "" {
+			continue
+		}
+		start, _ := lineOffsets(lines, i, i)
+		if selection, ok := SelectionFor(doc, start, len(doc)); ok {
+			return ContentEdit{Selection: selection, NewStr: "\n" + content}, true
+		}
+	}
+	return ContentEdit{}, false
+}
+
+// PlanPrepend works out a replace_content_range edit that adds content at the
+// start of doc, by replacing the opening lines with content followed by those
+// same lines. It returns false when doc is empty.
+func PlanPrepend(doc, content string) (ContentEdit, bool) {
+	lines := SplitLines(doc)
+	first := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		start, end := lineOffsets(lines, first, i+1)
+		if selection, ok := SelectionFor(doc, start, end); ok {
+			return ContentEdit{Selection: selection, NewStr: content + "\n" + doc[start:end]}, true
+		}
+	}
+	return ContentEdit{}, false
+}
+
+// SelectionFor returns a selection_with_ellipsis string that matches exactly
+// doc[start:end] and nothing else in doc.
+func SelectionFor(doc string, start, end int) (string, bool) {
+	target := doc[start:end]
+	if strings.TrimSpace(target) == "" {
+		return "", false
+	}
+
+	if len(target) <= maxInlineSelection {
+		if !strings.Contains(target, "...") && occurrences(doc, target) == 1 {
+			return target, true
+		}
+		return "", false
+	}
+
+	for n := 10; n*2 < len(target); n *= 2 {
+		prefix := target[:runeBoundary(target, n)]
+		suffix := target[runeBoundary(target, len(target)-n):]
+		if strings.Contains(prefix, "...") || strings.Contains(suffix, "...") {
+			continue
+		}
+		if occurrences(doc, prefix) != 1 {
+			continue
+		}
+		// The server matches the first occurrence of the suffix after the prefix.
+		rest := doc[start+len(prefix):]
+		idx := strings.Index(rest, suffix)
+		if idx >= 0 && start+len(prefix)+idx+len(suffix) == end {
+			return prefix + "..." + suffix, true
+		}
+	}
+
+	if !strings.Contains(target, "...") && occurrences(doc, target) == 1 {
+		return target, true
+	}
+	return "", false
+}
+
+// occurrences counts the matches of s in doc, including overlapping ones,
+// since the server may match any of them.
+func occurrences(doc, s string) int {
+	n := 0
+	for i := strings.Index(doc, s); i >= 0; {
+		n++
+		next := strings.Index(doc[i+1:], s)
+		if next < 0 {
+			break
+		}
+		i += 1 + next
+	}
+	return n
+}
+
+func lineOffsets(lines []string, start, end int) (int, int) {
+	off := 0
+	for i := 0; i < start; i++ {
+		off += len(lines[i]) + 1
+	}
+	startOff := off
+	for i := start; i < end; i++ {
+		off += len(lines[i]) + 1
+	}
+	return startOff, off - 1
+}
+
+// runeBoundary moves i back to the start of the rune containing it.
+func runeBoundary(s string, i int) int {
+	for i > 0 && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// EditText opens text in the user's editor ($VISUAL, $EDITOR, or vi) and
+// returns the saved result. The suffix sets the temp file extension so
+// editors pick the right syntax highlighting.
+func EditText(text, suffix string) (string, error) {
+	f, err := os.CreateTemp("", "notion-*"+suffix)
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	path := f.Name()
+	defer func() { _ = os.Remove(path) }()
+
+	if _, err := f.WriteString(text); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// Run through the shell so editors with arguments (e.g. "code --wait") work.
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %q failed: %w", strings.Fields(editor)[0], err)
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read edited file: %w", err)
+	}
+	return string(edited), nil
+}
+
+// SaveText writes text to a new temp file that is kept, so that edits which
+// couldn't be applied aren't lost, and returns its path.
+func SaveText(text, suffix string) (string, error) {
+	f, err := os.CreateTemp("", "notion-edit-*"+suffix)
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	if _, err := f.WriteString(text); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("write temp file: %w", err)
+	}
+	return f.Name(), nil
+}
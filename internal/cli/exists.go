@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+// IfExists policies say what a create does when the page already exists.
+const (
+	IfExistsCreate = "create" // create another page anyway
+	IfExistsSkip   = "skip"   // leave the existing page alone
+	IfExistsUpdate = "update" // update the existing page in place
+	IfExistsError  = "error"  // fail
+)
+
+// maxMatchCandidates caps how many search results are fetched to compare
+// their properties with --match.
+const maxMatchCandidates = 20
+
+// ExistingPage is a page that a create would duplicate.
+type ExistingPage struct {
+	ID    string
+	URL   string
+	Title string
+}
+
+// ParseMatch splits a --match key, Prop=Value, which identifies the database
+// row a create would duplicate.
+func ParseMatch(match string) (name, value string, err error) {
+	name, value, ok := strings.Cut(match, "=")
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if !ok || name == "" || value == "" {
+		return "", "", &output.UserError{Message: "--match must be Prop=Value, got " + match}
+	}
+	return name, value, nil
+}
+
+// PropertyMatches reports whether a fetched page's property has the given
+// value. Names and values are compared ignoring case, and a property with
+// several values, such as a multi-select, matches if any of them does.
+func PropertyMatches(props map[string]any, name, value string) bool {
+	v, ok := props[name]
+	if !ok {
+		for key, kv := range props {
+			if strings.EqualFold(key, name) {
+				v, ok = kv, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return false
+	}
+
+	// Notion returns multi-value properties as JSON-encoded lists.
+	if s, isString := v.(string); isString && strings.HasPrefix(s, "[") {
+		var items []any
+		if json.Unmarshal([]byte(s), &items) == nil {
+			v = items
+		}
+	}
+	values, isList := v.([]any)
+	if !isList {
+		values = []any{v}
+	}
+	for _, item := range values {
+		if s, ok := PropertyString(item); ok && SameTitle(s, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// SameTitle reports whether two titles name the same page, ignoring case and
+// surrounding space.
+func SameTitle(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// FindExistingPage looks for the page that creating req would duplicate: with
+// match, a row of the parent database whose property has that value;
+// otherwise a page with the same title under the same parent. More than one
+// candidate is an error, since it isn't clear which one is meant.
+func FindExistingPage(ctx context.Context, client *mcp.Client, req mcp.CreatePageRequest, match string) (*ExistingPage, error) {
+	found, err := FindExistingPages(ctx, client, req, match)
+	if err != nil {
+		return nil, err
+	}
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &found[0], nil
+	}
+	what := fmt.Sprintf("the title %q", req.Title)
+	if match != "" {
+		what = match
+	}
+	return nil, &output.UserError{Message: fmt.Sprintf("%d pages match %s; use --match with a unique property", len(found), what)}
+}
+
+// FindExistingPages returns every page FindExistingPage considers.
+func FindExistingPages(ctx context.Context, client *mcp.Client, req mcp.CreatePageRequest, match string) ([]ExistingPage, error) {
+	var found []ExistingPage
+
+	switch {
+	case match != "":
+		if req.ParentDatabaseID == "" {
+			return nil, &output.UserError{Message: "--match only works for database entries"}
+		}
+		name, value, err := ParseMatch(match)
+		if err != nil {
+			return nil, err
+		}
+		results, err := searchDataSource(ctx, client, req.ParentDatabaseID, value)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) > maxMatchCandidates {
+			return nil, incompleteLookup(match)
+		}
+		for _, r := range results {
+			page, err := client.Fetch(ctx, r.ID)
+			if err != nil {
+				return nil, err
+			}
+			if PropertyMatches(page.Properties(), name, value) {
+				found = append(found, ExistingPage{ID: r.ID, URL: page.URL, Title: page.Title})
+			}
+		}
+
+	case req.ParentDatabaseID != "":
+		results, err := searchDataSource(ctx, client, req.ParentDatabaseID, req.Title)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			if SameTitle(r.Title, req.Title) {
+				found = append(found, ExistingPage{ID: r.ID, URL: r.URL, Title: r.Title})
+			}
+		}
+
+	case req.ParentPageID != "":
+		parent, err := client.Fetch(ctx, req.ParentPageID)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range parent.Children() {
+			if child.Type != "page" || !SameTitle(child.Title, req.Title) {
+				continue
+			}
+			id := child.ID
+			if id == "" {
+				id, _ = ExtractNotionUUID(child.URL)
+			}
+			found = append(found, ExistingPage{ID: id, URL: child.URL, Title: child.Title})
+		}
+
+	default:
+		resp, err := client.Search(ctx, req.Title, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range resp.Results {
+			if r.Archived || r.ObjectType == "database" || !SameTitle(r.Title, req.Title) {
+				continue
+			}
+			page, err := client.Fetch(ctx, r.ID)
+			if err != nil {
+				return nil, err
+			}
+			if page.ParentType == "workspace" {
+				found = append(found, ExistingPage{ID: r.ID, URL: page.URL, Title: page.Title})
+			}
+		}
+	}
+
+	return found, nil
+}
+
+// searchDataSource searches the rows of a data source, leaving out archived
+// pages. The MCP server has no way to filter a data source by property, so
+// this goes through the search index: it can miss rows created moments ago,
+// and when it has more results than one page it fails rather than report a
+// row missing that it didn't see.
+func searchDataSource(ctx context.Context, client *mcp.Client, dataSourceID, query string) ([]mcp.SearchResult, error) {
+	resp, err := client.Search(ctx, query, &mcp.SearchOptions{DataSourceURL: "collection://" + dataSourceID})
+	if err != nil {
+		return nil, err
+	}
+	if resp.HasMore {
+		return nil, incompleteLookup(query)
+	}
+	results := make([]mcp.SearchResult, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		if !r.Archived {
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+func incompleteLookup(what string) error {
+	return &output.UserError{Message: fmt.Sprintf("too many rows look like %q to check whether it already exists; use --match with a more specific property", what)}
+}
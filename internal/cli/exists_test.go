@@ -0,0 +1,50 @@
+package cli
+
+import "testing"
+
+func TestParseMatch(t *testing.T) {
+	name, value, err := ParseMatch(" Ticket = ENG-42 ")
+	if err != nil || name != "Ticket" || value != "ENG-42" {
+		t.Errorf("ParseMatch() = %q, %q, %v", name, value, err)
+	}
+	name, value, err = ParseMatch("Query=a=b")
+	if err != nil || name != "Query" || value != "a=b" {
+		t.Errorf("ParseMatch() with = in value = %q, %q, %v", name, value, err)
+	}
+
+	for _, bad := range []string{"Ticket", "=ENG-42", "Ticket="} {
+		if _, _, err := ParseMatch(bad); err == nil {
+			t.Errorf("ParseMatch(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestPropertyMatches(t *testing.T) {
+	props := map[string]any{
+		"Ticket": "ENG-42",
+		"Tags":   `["api","db"]`,
+		"Owners": []any{"Ada", "Bob"},
+		"Points": 3.0,
+		"Done":   true,
+	}
+
+	tests := []struct {
+		name, value string
+		want        bool
+	}{
+		{"Ticket", "ENG-42", true},
+		{"ticket", "eng-42", true},
+		{"Ticket", "ENG-4", false},
+		{"Tags", "db", true},
+		{"Tags", "web", false},
+		{"Owners", "bob", true},
+		{"Points", "3", true},
+		{"Done", "true", true},
+		{"Missing", "x", false},
+	}
+	for _, tt := range tests {
+		if got := PropertyMatches(props, tt.name, tt.value); got != tt.want {
+			t.Errorf("PropertyMatches(%q, %q) = %v, want %v", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
@@ -1,17 +1,51 @@
 package cli
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 const frontmatterDelimiter = "---"
 
+// Frontmatter keys with a fixed meaning. Any other key is treated as a
+// database property.
+const (
+	FrontmatterNotionID = "notion-id"
+	FrontmatterSyncedAt = "notion-synced-at"
+	FrontmatterTitle    = "title"
+	FrontmatterIcon     = "icon"
+	FrontmatterCover    = "cover"
+	FrontmatterParent   = "parent"
+	FrontmatterParentDB = "parent-db"
+	FrontmatterVars     = "vars"
+)
+
 type Frontmatter struct {
 	NotionID string
+	SyncedAt time.Time
+	Title    string
+	Icon     string
+	Cover    string
+	Parent   string
+	ParentDB string
+
+	// Vars holds default values for a page template's {{placeholders}}.
+	Vars map[string]string
+
+	// Properties holds the remaining keys with values flattened to the
+	// strings CoerceProperties accepts: lists are comma-separated and date
+	// ranges ({start, end}) become "start/end".
+	Properties map[string]string
 }
 
 // ParseFrontmatter extracts frontmatter and body from a markdown string.
 // Returns the parsed frontmatter (if any) and the body without frontmatter.
+// Frontmatter that isn't valid YAML is scanned for top-level notion-id and
+// notion-synced-at lines only.
 func ParseFrontmatter(content string) (Frontmatter, string) {
 	trimmed := strings.TrimLeft(content, " \t")
 	if !strings.HasPrefix(trimmed, frontmatterDelimiter) {
@@ -40,6 +74,79 @@ func ParseFrontmatter(content string) (Frontmatter, string) {
 
 	body := strings.TrimLeft(afterClose, "\r\n")
 
+	var values map[string]any
+	if err := yaml.Unmarshal([]byte(fmBlock), &values); err != nil {
+		return parseFrontmatterLines(fmBlock), body
+	}
+
+	fm := Frontmatter{}
+	for k, v := range values {
+		if v == nil {
+			continue
+		}
+		switch k {
+		case FrontmatterSyncedAt:
+			switch t := v.(type) {
+			case time.Time:
+				fm.SyncedAt = t
+			case string:
+				fm.SyncedAt, _ = time.Parse(time.RFC3339, t)
+			}
+			continue
+		case FrontmatterVars:
+			vars, _ := v.(map[string]any)
+			for name, value := range vars {
+				if s, ok := PropertyString(value); ok {
+					if fm.Vars == nil {
+						fm.Vars = map[string]string{}
+					}
+					fm.Vars[name] = s
+				}
+			}
+			continue
+		}
+
+		value, ok := PropertyString(v)
+		if !ok {
+			continue
+		}
+		switch k {
+		case FrontmatterNotionID:
+			fm.NotionID = value
+		case FrontmatterTitle:
+			fm.Title = value
+		case FrontmatterIcon:
+			fm.Icon = value
+		case FrontmatterCover:
+			fm.Cover = value
+		case FrontmatterParent:
+			fm.Parent = value
+		case FrontmatterParentDB:
+			fm.ParentDB = value
+		default:
+			if fm.Properties == nil {
+				fm.Properties = map[string]string{}
+			}
+			fm.Properties[k] = value
+		}
+	}
+
+	return fm, body
+}
+
+// CheckFrontmatter returns the YAML error in content's frontmatter, if any.
+// ParseFrontmatter doesn't report it, as synced files must stay readable.
+func CheckFrontmatter(content string) error {
+	var values map[string]any
+	if err := yaml.Unmarshal([]byte(extractFrontmatterBlock(content)), &values); err != nil {
+		return fmt.Errorf("invalid frontmatter: %w", err)
+	}
+	return nil
+}
+
+// parseFrontmatterLines reads the notion-cli keys from frontmatter that
+// isn't valid YAML.
+func parseFrontmatterLines(fmBlock string) Frontmatter {
 	fm := Frontmatter{}
 	for _, line := range strings.Split(fmBlock, "\n") {
 		trimLine := strings.TrimRight(line, " \t\r")
@@ -54,48 +161,123 @@ func ParseFrontmatter(content string) (Frontmatter, string) {
 			continue
 		}
 		k = strings.TrimSpace(k)
-		v = strings.TrimSpace(v)
-		if k == "notion-id" {
+		v = strings.Trim(strings.TrimSpace(v), `"'`)
+		switch k {
+		case FrontmatterNotionID:
 			fm.NotionID = v
+		case FrontmatterSyncedAt:
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				fm.SyncedAt = t
+			}
 		}
 	}
+	return fm
+}
 
-	return fm, body
+// PropertyString flattens a decoded YAML or JSON value into a property string.
+// Nested maps other than date ranges are not supported.
+func PropertyString(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case int:
+		return strconv.Itoa(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 && v.Location() == time.UTC {
+			return v.Format("2006-01-02"), true
+		}
+		return v.Format(time.RFC3339), true
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := PropertyString(item)
+			if !ok {
+				return "", false
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ", "), true
+	case map[string]any:
+		start, ok := PropertyString(v["start"])
+		if !ok {
+			return "", false
+		}
+		if end, ok := PropertyString(v["end"]); ok && end != "" {
+			return start + "/" + end, true
+		}
+		return start, true
+	}
+	return "", false
 }
 
 // SetFrontmatterID returns the content with notion-id set in frontmatter.
 // If frontmatter already exists, it updates or adds the notion-id field.
 // If no frontmatter exists, it prepends a new frontmatter block.
 func SetFrontmatterID(content string, notionID string) string {
+	return SetFrontmatterField(content, FrontmatterNotionID, notionID)
+}
+
+// SetFrontmatterSyncedAt returns the content with notion-synced-at set to t.
+func SetFrontmatterSyncedAt(content string, t time.Time) string {
+	return SetFrontmatterField(content, FrontmatterSyncedAt, t.UTC().Format(time.RFC3339))
+}
+
+// SetFrontmatterField returns the content with a top-level frontmatter key set
+// to value, leaving all other lines untouched.
+func SetFrontmatterField(content, key, value string) string {
 	hasTrailingNewline := strings.HasSuffix(content, "\n")
 	_, body := ParseFrontmatter(content)
 
 	fmBlock := extractFrontmatterBlock(content)
 	if fmBlock == "" {
-		return ensureTrailingNewline(frontmatterDelimiter+"\nnotion-id: "+notionID+"\n"+frontmatterDelimiter+"\n\n"+body, hasTrailingNewline)
+		return ensureTrailingNewline(frontmatterDelimiter+"\n"+key+": "+value+"\n"+frontmatterDelimiter+"\n\n"+body, hasTrailingNewline)
 	}
 
 	var newLines []string
-	replaced := false
+	replaced, skipping := false, false
 	for _, line := range strings.Split(fmBlock, "\n") {
 		trimLine := strings.TrimRight(line, " \t\r")
-		isTopLevel := !strings.HasPrefix(trimLine, " ") && !strings.HasPrefix(trimLine, "\t")
+		if trimLine == "" {
+			newLines = append(newLines, line)
+			continue
+		}
+		isTopLevel := !strings.HasPrefix(trimLine, " ") && !strings.HasPrefix(trimLine, "\t") && !strings.HasPrefix(trimLine, "- ")
+		if skipping && !isTopLevel {
+			// Drop the nested lines of a block value being replaced.
+			continue
+		}
+		skipping = false
 		if isTopLevel {
-			if k, _, ok := strings.Cut(trimLine, ":"); ok && strings.TrimSpace(k) == "notion-id" {
-				newLines = append(newLines, "notion-id: "+notionID)
+			if k, _, ok := strings.Cut(trimLine, ":"); ok && strings.TrimSpace(k) == key {
+				newLines = append(newLines, key+": "+value)
 				replaced = true
+				skipping = true
 				continue
 			}
 		}
 		newLines = append(newLines, line)
 	}
 	if !replaced {
-		newLines = append(newLines, "notion-id: "+notionID)
+		newLines = append(newLines, key+": "+value)
 	}
 
 	return ensureTrailingNewline(frontmatterDelimiter+"\n"+strings.Join(newLines, "\n")+"\n"+frontmatterDelimiter+"\n\n"+body, hasTrailingNewline)
 }
 
+// ReplaceBody returns the content with its body replaced, keeping any
+// frontmatter block exactly as it was.
+func ReplaceBody(content, body string) string {
+	fmBlock := extractFrontmatterBlock(content)
+	if fmBlock == "" {
+		return body
+	}
+	return frontmatterDelimiter + "\n" + fmBlock + "\n" + frontmatterDelimiter + "\n\n" + body
+}
+
 func ensureTrailingNewline(s string, want bool) string {
 	has := strings.HasSuffix(s, "\n")
 	if want && !has {
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"testing"
+	"time"
 )
 
 func TestParseFrontmatter(t *testing.T) {
@@ -68,6 +69,72 @@ func TestParseFrontmatter(t *testing.T) {
 	}
 }
 
+func TestParseFrontmatterYAML(t *testing.T) {
+	input := `---
+notion-id: abc123
+notion-synced-at: 2026-01-02T03:04:05Z
+title: "Launch: phase 2"
+icon: 🚀
+cover: https://example.com/cover.png
+parent: Engineering
+tags:
+  - backend
+  - api
+due:
+  start: 2026-03-01
+  end: 2026-03-05
+points: 3
+draft: false
+vars:
+  severity: SEV3
+  oncall: true
+---
+
+# Hello`
+
+	fm, body := ParseFrontmatter(input)
+	if body != "# Hello" {
+		t.Errorf("body = %q", body)
+	}
+	if fm.NotionID != "abc123" || fm.Title != "Launch: phase 2" || fm.Icon != "🚀" || fm.Parent != "Engineering" {
+		t.Errorf("fm = %+v", fm)
+	}
+	if fm.Cover != "https://example.com/cover.png" {
+		t.Errorf("Cover = %q", fm.Cover)
+	}
+	if want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC); !fm.SyncedAt.Equal(want) {
+		t.Errorf("SyncedAt = %v, want %v", fm.SyncedAt, want)
+	}
+	if len(fm.Vars) != 2 || fm.Vars["severity"] != "SEV3" || fm.Vars["oncall"] != "true" {
+		t.Errorf("Vars = %v", fm.Vars)
+	}
+
+	wantProps := map[string]string{
+		"tags":   "backend, api",
+		"due":    "2026-03-01/2026-03-05",
+		"points": "3",
+		"draft":  "false",
+	}
+	if len(fm.Properties) != len(wantProps) {
+		t.Errorf("Properties = %v, want %v", fm.Properties, wantProps)
+	}
+	for k, v := range wantProps {
+		if fm.Properties[k] != v {
+			t.Errorf("Properties[%q] = %q, want %q", k, fm.Properties[k], v)
+		}
+	}
+}
+
+func TestParseFrontmatterInvalidYAML(t *testing.T) {
+	fm, body := ParseFrontmatter("---\nnotion-id: abc123\ntitle: [unclosed\n---\n\n# Hello")
+	if fm.NotionID != "abc123" {
+		t.Errorf("NotionID = %q, want abc123", fm.NotionID)
+	}
+	if body != "# Hello" {
+		t.Errorf("body = %q", body)
+	}
+}
+
 func TestSetFrontmatterID(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -111,6 +178,18 @@ func TestSetFrontmatterID(t *testing.T) {
 			id:    "new-id",
 			want:  "---\nparent:\n  notion-id: nested\nnotion-id: new-id\n---\n\n# Hello",
 		},
+		{
+			name:  "preserves comments, quoting and lists",
+			input: "---\n# Project notes\ntitle: 'Plan'\ntags:\n  - a   # first\n  - b\n\nnotion-id: old-id\nstatus: \"Done\"\n---\n\n# Hello",
+			id:    "new-id",
+			want:  "---\n# Project notes\ntitle: 'Plan'\ntags:\n  - a   # first\n  - b\n\nnotion-id: new-id\nstatus: \"Done\"\n---\n\n# Hello",
+		},
+		{
+			name:  "replaces a block value",
+			input: "---\nnotion-id:\n  - stray\ntitle: Plan\n---\n\n# Hello",
+			id:    "new-id",
+			want:  "---\nnotion-id: new-id\ntitle: Plan\n---\n\n# Hello",
+		},
 	}
 
 	for _, tt := range tests {
@@ -122,3 +201,40 @@ func TestSetFrontmatterID(t *testing.T) {
 		})
 	}
 }
+
+func TestParseFrontmatterSyncedAt(t *testing.T) {
+	fm, _ := ParseFrontmatter("---\nnotion-id: abc\nnotion-synced-at: 2026-01-02T03:04:05Z\n---\n\nBody")
+	want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if !fm.SyncedAt.Equal(want) {
+		t.Errorf("SyncedAt = %v, want %v", fm.SyncedAt, want)
+	}
+}
+
+func TestSetFrontmatterSyncedAt(t *testing.T) {
+	input := "---\nnotion-id: abc\n---\n\n# Hello\n"
+	got := SetFrontmatterSyncedAt(input, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	want := "---\nnotion-id: abc\nnotion-synced-at: 2026-01-02T03:04:05Z\n---\n\n# Hello\n"
+	if got != want {
+		t.Errorf("SetFrontmatterSyncedAt():\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestReplaceBody(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		body  string
+		want  string
+	}{
+		{"keeps frontmatter", "---\nnotion-id: abc\ntags: [a, b]\n---\n\nOld body\n", "New body\n", "---\nnotion-id: abc\ntags: [a, b]\n---\n\nNew body\n"},
+		{"no frontmatter", "Old body\n", "New body\n", "New body\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReplaceBody(tt.input, tt.body); got != tt.want {
+				t.Errorf("ReplaceBody():\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
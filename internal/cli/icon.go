@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/lox/notion-cli/internal/output"
+	"github.com/yuin/goldmark-emoji/definition"
+)
+
+var (
+	emojiOnce sync.Once
+	emojis    definition.Emojis
+)
+
+// ResolveIcon turns an icon given as a flag or in frontmatter into the value
+// Notion expects: an emoji, or the URL of an image. :shortcode: names, as used
+// on GitHub and Slack, are converted to their emoji.
+func ResolveIcon(icon string) (string, error) {
+	icon = strings.TrimSpace(icon)
+	switch {
+	case icon == "":
+		return "", nil
+	case isImageURL(icon):
+		return icon, nil
+	case len(icon) > 2 && strings.HasPrefix(icon, ":") && strings.HasSuffix(icon, ":"):
+		emojiOnce.Do(func() { emojis = definition.Github() })
+		if e, ok := emojis.Get(strings.Trim(icon, ":")); ok && e.IsUnicode() {
+			return string(e.Unicode), nil
+		}
+		return "", &output.UserError{Message: "unknown emoji shortcode: " + icon}
+	}
+
+	if r, _ := utf8.DecodeRuneInString(icon); IsEmoji(r) {
+		return icon, nil
+	}
+	return "", &output.UserError{Message: "icon must be an emoji, a :shortcode: or an image URL: " + icon}
+}
+
+// ResolveCover checks a cover image, which must be a URL.
+func ResolveCover(cover string) (string, error) {
+	cover = strings.TrimSpace(cover)
+	if cover == "" || isImageURL(cover) {
+		return cover, nil
+	}
+	return "", &output.UserError{Message: "cover must be an image URL: " + cover}
+}
+
+func isImageURL(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
@@ -0,0 +1,33 @@
+package cli
+
+import "testing"
+
+func TestResolveIcon(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"", "", false},
+		{"🚀", "🚀", false},
+		{" :rocket: ", "🚀", false},
+		{":memo:", "📝", false},
+		{"https://example.com/icon.png", "https://example.com/icon.png", false},
+		{":no-such-emoji:", "", true},
+		{"rocket", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ResolveIcon(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ResolveIcon(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestResolveCover(t *testing.T) {
+	if got, err := ResolveCover("https://example.com/c.jpg"); err != nil || got != "https://example.com/c.jpg" {
+		t.Errorf("ResolveCover(url) = %q, %v", got, err)
+	}
+	if _, err := ResolveCover("./cover.jpg"); err == nil {
+		t.Error("ResolveCover() accepted a local path")
+	}
+}
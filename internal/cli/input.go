@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/lox/notion-cli/internal/output"
+	"golang.org/x/term"
+)
+
+// RefsFromArgs returns the page references given as arguments. When there are
+// no arguments, or the only argument is "-", references are read from stdin,
+// one per line.
+func RefsFromArgs(args []string) ([]string, error) {
+	if len(args) == 0 || (len(args) == 1 && args[0] == "-") {
+		return ReadRefList(os.Stdin)
+	}
+	return args, nil
+}
+
+// ReadRefList reads newline-separated references, skipping blank lines and
+// lines starting with #.
+func ReadRefList(r io.Reader) ([]string, error) {
+	var refs []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		refs = append(refs, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read references: %w", err)
+	}
+	return refs, nil
+}
+
+// ReadContent returns value, or everything on stdin when value is "-".
+func ReadContent(value string) (string, error) {
+	if value != "-" {
+		return value, nil
+	}
+	return readStdin()
+}
+
+// ReadContentFile returns the contents of path, or everything on stdin when
+// path is "-".
+func ReadContentFile(path string) (string, error) {
+	if path == "-" {
+		return readStdin()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// StdinOnce checks that at most one of a command's content flags is "-", as
+// stdin can only be read once.
+func StdinOnce(values ...string) error {
+	n := 0
+	for _, v := range values {
+		if v == "-" {
+			n++
+		}
+	}
+	if n > 1 {
+		return &output.UserError{Message: "only one flag can read from stdin (-)"}
+	}
+	return nil
+}
+
+func readStdin() (string, error) {
+	if IsInteractive() {
+		fmt.Fprintln(os.Stderr, "Reading content from stdin (press Ctrl-D when done)...")
+	}
+	return readAllContent(os.Stdin)
+}
+
+// readAllContent reads piped content, dropping the trailing newline that
+// commands like echo and git log end their output with.
+func readAllContent(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("read stdin: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// IsInteractive reports whether stdin is a terminal.
+func IsInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// Confirm asks a yes/no question on the terminal. It returns true without
+// prompting when stdin is not a terminal.
+func Confirm(prompt string) (bool, error) {
+	if !IsInteractive() {
+		return true, nil
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadRefList(t *testing.T) {
+	input := "abc123\n\n  Meeting Notes  \n# comment\nhttps://www.notion.so/Page-12345678abcdef1234567890abcdef12\n"
+	got, err := ReadRefList(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadRefList() error = %v", err)
+	}
+	want := []string{"abc123", "Meeting Notes", "https://www.notion.so/Page-12345678abcdef1234567890abcdef12"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadRefList() = %q, want %q", got, want)
+	}
+}
+
+func TestRefsFromArgs(t *testing.T) {
+	args := []string{"one", "two"}
+	got, err := RefsFromArgs(args)
+	if err != nil {
+		t.Fatalf("RefsFromArgs() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, args) {
+		t.Errorf("RefsFromArgs() = %q, want %q", got, args)
+	}
+}
+
+func TestReadAllContent(t *testing.T) {
+	large := strings.Repeat("line of output\n", 100000)
+	got, err := readAllContent(strings.NewReader(large))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.TrimSuffix(large, "\n"); got != want {
+		t.Errorf("readAllContent() returned %d bytes, want %d", len(got), len(want))
+	}
+}
+
+func TestStdinOnce(t *testing.T) {
+	if err := StdinOnce("-", "text", ""); err != nil {
+		t.Errorf("StdinOnce() with one - = %v", err)
+	}
+	if err := StdinOnce("-", "", "-"); err == nil {
+		t.Error("StdinOnce() accepted two flags reading stdin")
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// LocalLink is a Markdown link or image whose target is a relative path.
+type LocalLink struct {
+	Image    bool
+	Text     string
+	Target   string // as written, including any #fragment
+	Path     string // target resolved against the document's directory
+	Fragment string
+}
+
+// IsMarkdown reports whether the link points at another Markdown file.
+func (l LocalLink) IsMarkdown() bool {
+	return strings.EqualFold(filepath.Ext(l.Path), ".md")
+}
+
+// mdLinkRe matches [text](target) and ![alt](target "title"), with the target
+// optionally wrapped in angle brackets.
+var mdLinkRe = regexp.MustCompile(`(!?)\[([^\]]*)\]\(\s*(<[^>]+>|[^)\s]+)(\s+"[^"]*")?\s*\)`)
+
+// RewriteLocalLinks calls fn for every link in markdown whose target is a
+// relative path, resolving it against baseDir. If fn returns ok, the whole
+// link is replaced with the returned text. Links inside code blocks and
+// inline code are left alone.
+func RewriteLocalLinks(markdown, baseDir string, fn func(LocalLink) (string, bool)) string {
+	lines := strings.SplitAfter(markdown, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !strings.Contains(line, "](") {
+			continue
+		}
+		lines[i] = rewriteLine(line, baseDir, fn)
+	}
+	return strings.Join(lines, "")
+}
+
+// LocalLinks returns the links in markdown whose targets are relative paths.
+func LocalLinks(markdown, baseDir string) []LocalLink {
+	var links []LocalLink
+	RewriteLocalLinks(markdown, baseDir, func(l LocalLink) (string, bool) {
+		links = append(links, l)
+		return "", false
+	})
+	return links
+}
+
+func rewriteLine(line, baseDir string, fn func(LocalLink) (string, bool)) string {
+	// Split out inline code spans so links inside them are skipped.
+	parts := strings.Split(line, "`")
+	for i := 0; i < len(parts); i += 2 {
+		parts[i] = mdLinkRe.ReplaceAllStringFunc(parts[i], func(match string) string {
+			m := mdLinkRe.FindStringSubmatch(match)
+			link, ok := parseLocalLink(m[1] == "!", m[2], m[3], baseDir)
+			if !ok {
+				return match
+			}
+			if replacement, ok := fn(link); ok {
+				return replacement
+			}
+			return match
+		})
+	}
+	return strings.Join(parts, "`")
+}
+
+func parseLocalLink(image bool, text, target, baseDir string) (LocalLink, bool) {
+	target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+	if target == "" || strings.HasPrefix(target, "#") || strings.HasPrefix(target, "/") || strings.Contains(target, ":") {
+		return LocalLink{}, false
+	}
+
+	pathPart, fragment, _ := strings.Cut(target, "#")
+	if unescaped, err := url.PathUnescape(pathPart); err == nil {
+		pathPart = unescaped
+	}
+	if pathPart == "" {
+		return LocalLink{}, false
+	}
+
+	return LocalLink{
+		Image:    image,
+		Text:     text,
+		Target:   target,
+		Path:     filepath.Join(baseDir, filepath.FromSlash(pathPart)),
+		Fragment: fragment,
+	}, true
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalLinks(t *testing.T) {
+	markdown := "# Doc\n\n" +
+		"![diagram](./img/arch.png \"Architecture\")\n" +
+		"See [the RFC](../rfcs/0042.md#motivation) and [spec](<my spec.pdf>).\n" +
+		"External [site](https://example.com), [anchor](#top), [mail](mailto:a@b.c), [abs](/etc/hosts).\n" +
+		"Inline `[code](skip.md)` is ignored.\n" +
+		"```\n[fenced](skip.md)\n```\n" +
+		"[escaped](my%20file.txt)\n"
+
+	links := LocalLinks(markdown, "docs")
+
+	want := []LocalLink{
+		{Image: true, Text: "diagram", Target: "./img/arch.png", Path: filepath.Join("docs", "img", "arch.png")},
+		{Text: "the RFC", Target: "../rfcs/0042.md#motivation", Path: "rfcs/0042.md", Fragment: "motivation"},
+		{Text: "spec", Target: "my spec.pdf", Path: filepath.Join("docs", "my spec.pdf")},
+		{Text: "escaped", Target: "my%20file.txt", Path: filepath.Join("docs", "my file.txt")},
+	}
+	if len(links) != len(want) {
+		t.Fatalf("LocalLinks() returned %d links, want %d: %+v", len(links), len(want), links)
+	}
+	for i := range want {
+		if links[i] != want[i] {
+			t.Errorf("link %d = %+v, want %+v", i, links[i], want[i])
+		}
+	}
+	if !links[1].IsMarkdown() || links[0].IsMarkdown() {
+		t.Error("IsMarkdown() misclassified links")
+	}
+}
+
+func TestRewriteLocalLinks(t *testing.T) {
+	markdown := "![a](a.png) and `![b](b.png)` and [c](c.md)\n"
+	got := RewriteLocalLinks(markdown, ".", func(l LocalLink) (string, bool) {
+		if l.IsMarkdown() {
+			return "", false
+		}
+		return "![" + l.Text + "](https://files.example/" + l.Target + ")", true
+	})
+	want := "![a](https://files.example/a.png) and `![b](b.png)` and [c](c.md)\n"
+	if got != want {
+		t.Errorf("RewriteLocalLinks() = %q, want %q", got, want)
+	}
+}
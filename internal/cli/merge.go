@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	conflictStart = "<<<<<<< "
+	conflictSep   = "======="
+	conflictEnd   = ">>>>>>> "
+)
+
+type sideHunk struct {
+	Hunk
+	remote bool
+}
+
+func (h sideHunk) end() int {
+	return h.OldStart + len(h.OldLines)
+}
+
+// Merge3 performs a line-based three-way merge of local and remote changes
+// made since base. Regions changed on only one side are taken from that side;
+// regions changed identically on both are taken once. Overlapping different
+// changes are written as git-style conflict blocks labelled with localLabel
+// and remoteLabel. It returns the merged text and the number of conflicts.
+func Merge3(base, local, remote, localLabel, remoteLabel string) (string, int) {
+	baseLines := SplitLines(base)
+
+	var hunks []sideHunk
+	for _, h := range Hunks(DiffLines(baseLines, SplitLines(local))) {
+		hunks = append(hunks, sideHunk{Hunk: h})
+	}
+	for _, h := range Hunks(DiffLines(baseLines, SplitLines(remote))) {
+		hunks = append(hunks, sideHunk{Hunk: h, remote: true})
+	}
+	sort.SliceStable(hunks, func(i, j int) bool {
+		return hunks[i].OldStart < hunks[j].OldStart
+	})
+
+	var out []string
+	conflicts := 0
+	cursor := 0
+
+	for i := 0; i < len(hunks); {
+		// Group hunks whose base ranges overlap or touch.
+		start, end := hunks[i].OldStart, hunks[i].end()
+		j := i + 1
+		for j < len(hunks) && hunks[j].OldStart <= end {
+			if e := hunks[j].end(); e > end {
+				end = e
+			}
+			j++
+		}
+		group := hunks[i:j]
+		i = j
+
+		out = append(out, baseLines[cursor:start]...)
+		cursor = end
+
+		var hasLocal, hasRemote bool
+		for _, h := range group {
+			if h.remote {
+				hasRemote = true
+			} else {
+				hasLocal = true
+			}
+		}
+
+		localLines := applySide(baseLines, start, end, group, false)
+		remoteLines := applySide(baseLines, start, end, group, true)
+
+		switch {
+		case !hasRemote:
+			out = append(out, localLines...)
+		case !hasLocal:
+			out = append(out, remoteLines...)
+		case strings.Join(localLines, "\n") == strings.Join(remoteLines, "\n"):
+			out = append(out, localLines...)
+		default:
+			conflicts++
+			out = append(out, conflictStart+localLabel)
+			out = append(out, localLines...)
+			out = append(out, conflictSep)
+			out = append(out, remoteLines...)
+			out = append(out, conflictEnd+remoteLabel)
+		}
+	}
+	out = append(out, baseLines[cursor:]...)
+
+	merged := strings.Join(out, "\n")
+	if len(out) > 0 && (strings.HasSuffix(local, "\n") || strings.HasSuffix(remote, "\n")) {
+		merged += "\n"
+	}
+	return merged, conflicts
+}
+
+// Merge3Rebased is Merge3 for when the remote side is written differently from
+// the local one, as Markdown rendered from Notion is. Remote changes are found
+// by comparing remote with remoteBase, the base as the remote side writes it,
+// and carried over to base, so list markers, emphasis, numbering or blank
+// lines that only differ in how the two sides write them are not taken as
+// remote edits.
+func Merge3Rebased(base, local, remoteBase, remote, localLabel, remoteLabel string) (string, int) {
+	rebased := rebaseLines(SplitLines(base), SplitLines(remoteBase), SplitLines(remote))
+	remoteText := strings.Join(rebased, "\n")
+	if len(rebased) > 0 && strings.HasSuffix(remote, "\n") {
+		remoteText += "\n"
+	}
+	return Merge3(base, local, remoteText, localLabel, remoteLabel)
+}
+
+// rebaseLines applies the changes from remoteBase to remote to base, which has
+// the same content as remoteBase written differently. Lines are paired when
+// they match once formatting is normalised. A change next to lines that are
+// written differently takes the remote's version of those lines too.
+func rebaseLines(base, remoteBase, remote []string) []string {
+	align := Hunks(DiffLines(lineKeys(base), lineKeys(remoteBase)))
+	changes := Hunks(DiffLines(remoteBase, remote))
+
+	type region struct {
+		start, end int // range in remoteBase
+		changes    []Hunk
+	}
+	var regions []region
+	for _, c := range changes {
+		start, end := c.OldStart, c.OldStart+len(c.OldLines)
+		for grown := true; grown; {
+			grown = false
+			for _, a := range align {
+				aStart, aEnd := a.NewStart, a.NewStart+len(a.NewLines)
+				if !rangesTouch(aStart, aEnd, start, end) || (aStart >= start && aEnd <= end) {
+					continue
+				}
+				start, end = min(start, aStart), max(end, aEnd)
+				grown = true
+			}
+		}
+		if n := len(regions); n > 0 && start <= regions[n-1].end {
+			regions[n-1].end = max(regions[n-1].end, end)
+			regions[n-1].changes = append(regions[n-1].changes, c)
+			continue
+		}
+		regions = append(regions, region{start: start, end: end, changes: []Hunk{c}})
+	}
+
+	// baseIndex maps a line boundary in remoteBase that isn't inside a
+	// differently written run to the same boundary in base. Lines only base
+	// has at the boundary come before it for a start and after it for an end.
+	baseIndex := func(i int, isEnd bool) int {
+		for _, a := range align {
+			aEnd := a.NewStart + len(a.NewLines)
+			if aEnd < i || aEnd == i && (isEnd || len(a.NewLines) > 0) {
+				i += len(a.OldLines) - len(a.NewLines)
+			}
+		}
+		return i
+	}
+
+	var out []string
+	cursor := 0
+	for _, r := range regions {
+		start := baseIndex(r.start, false)
+		out = append(out, base[cursor:start]...)
+		from := r.start
+		for _, c := range r.changes {
+			out = append(out, remoteBase[from:c.OldStart]...)
+			out = append(out, c.NewLines...)
+			from = c.OldStart + len(c.OldLines)
+		}
+		out = append(out, remoteBase[from:r.end]...)
+		cursor = baseIndex(r.end, true)
+	}
+	return append(out, base[cursor:]...)
+}
+
+// rangesTouch reports whether a run of differently written lines [aStart,
+// aEnd) overlaps a change to [start, end). Either may be empty: an insertion
+// between lines, or lines one side doesn't have.
+func rangesTouch(aStart, aEnd, start, end int) bool {
+	switch {
+	case aStart == aEnd:
+		return start <= aStart && aStart <= end
+	case start == end:
+		return aStart < start && start < aEnd
+	}
+	return aStart < end && start < aEnd
+}
+
+var (
+	orderedMarkerRe = regexp.MustCompile(`^\d+[.)] `)
+	bulletMarkerRe  = regexp.MustCompile(`^[*+] `)
+)
+
+// lineKeys normalises lines for pairing them across the two sides: indentation,
+// bullet and number markers and emphasis style are ignored.
+func lineKeys(lines []string) []string {
+	keys := make([]string, len(lines))
+	for i, line := range lines {
+		key := strings.TrimSpace(line)
+		key = bulletMarkerRe.ReplaceAllString(key, "- ")
+		key = orderedMarkerRe.ReplaceAllString(key, "1. ")
+		keys[i] = strings.ReplaceAll(key, "_", "*")
+	}
+	return keys
+}
+
+// applySide rebuilds base[start:end] with one side's hunks from the group applied.
+func applySide(base []string, start, end int, group []sideHunk, remote bool) []string {
+	var out []string
+	cursor := start
+	for _, h := range group {
+		if h.remote != remote {
+			continue
+		}
+		out = append(out, base[cursor:h.OldStart]...)
+		out = append(out, h.NewLines...)
+		cursor = h.end()
+	}
+	return append(out, base[cursor:end]...)
+}
+
+// HasConflictMarkers reports whether text contains unresolved conflict blocks.
+func HasConflictMarkers(text string) bool {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, conflictStart) || strings.HasPrefix(line, conflictEnd) {
+			return true
+		}
+	}
+	return false
+}
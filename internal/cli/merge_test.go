@@ -0,0 +1,139 @@
+package cli
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	base := "# Title\n\nIntro\n\nMiddle\n\nOutro\n"
+
+	tests := []struct {
+		name          string
+		local         string
+		remote        string
+		want          string
+		wantConflicts int
+	}{
+		{
+			name:   "no changes",
+			local:  base,
+			remote: base,
+			want:   base,
+		},
+		{
+			name:   "local only",
+			local:  "# Title\n\nIntro edited\n\nMiddle\n\nOutro\n",
+			remote: base,
+			want:   "# Title\n\nIntro edited\n\nMiddle\n\nOutro\n",
+		},
+		{
+			name:   "remote only",
+			local:  base,
+			remote: "# Title\n\nIntro\n\nMiddle\n\nOutro edited\n",
+			want:   "# Title\n\nIntro\n\nMiddle\n\nOutro edited\n",
+		},
+		{
+			name:   "non-overlapping changes",
+			local:  "# Title\n\nIntro edited\n\nMiddle\n\nOutro\n",
+			remote: "# Title\n\nIntro\n\nMiddle\n\nOutro edited\n",
+			want:   "# Title\n\nIntro edited\n\nMiddle\n\nOutro edited\n",
+		},
+		{
+			name:   "identical changes",
+			local:  "# Title\n\nSame edit\n\nMiddle\n\nOutro\n",
+			remote: "# Title\n\nSame edit\n\nMiddle\n\nOutro\n",
+			want:   "# Title\n\nSame edit\n\nMiddle\n\nOutro\n",
+		},
+		{
+			name:          "conflicting changes",
+			local:         "# Title\n\nLocal intro\n\nMiddle\n\nOutro\n",
+			remote:        "# Title\n\nRemote intro\n\nMiddle\n\nOutro\n",
+			want:          "# Title\n\n<<<<<<< local\nLocal intro\n=======\nRemote intro\n>>>>>>> notion\n\nMiddle\n\nOutro\n",
+			wantConflicts: 1,
+		},
+		{
+			name:          "both append different lines at end",
+			local:         base + "Local tail\n",
+			remote:        base + "Remote tail\n",
+			want:          base + "<<<<<<< local\nLocal tail\n=======\nRemote tail\n>>>>>>> notion\n",
+			wantConflicts: 1,
+		},
+		{
+			name:   "local appends at end",
+			local:  base + "Local tail\n",
+			remote: base,
+			want:   base + "Local tail\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := Merge3(base, tt.local, tt.remote, "local", "notion")
+			if conflicts != tt.wantConflicts {
+				t.Errorf("Merge3() conflicts = %d, want %d", conflicts, tt.wantConflicts)
+			}
+			if got != tt.want {
+				t.Errorf("Merge3():\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasConflictMarkers(t *testing.T) {
+	if !HasConflictMarkers("a\n<<<<<<< local\nb\n=======\nc\n>>>>>>> notion\n") {
+		t.Error("HasConflictMarkers() = false, want true")
+	}
+	if HasConflictMarkers("a\n=======\nb") {
+		t.Error("HasConflictMarkers() = true for a plain separator, want false")
+	}
+}
+
+func TestMerge3Rebased(t *testing.T) {
+	// The file as written locally, and the same content as Notion renders it.
+	base := "# Plan\n\n* one\n* two\n\n3) first\n4) second\n\n_note_ here\n"
+	rendered := "# Plan\n\n- one\n- two\n\n1. first\n2. second\n\n*note* here\n"
+
+	tests := []struct {
+		name          string
+		local         string
+		remote        string
+		want          string
+		wantConflicts int
+	}{
+		{
+			name:   "local only, remote a formatting round trip",
+			local:  "# Plan\n\n* one\n* two edited\n\n3) first\n4) second\n\n_note_ here\n",
+			remote: rendered,
+			want:   "# Plan\n\n* one\n* two edited\n\n3) first\n4) second\n\n_note_ here\n",
+		},
+		{
+			name:   "remote edit keeps local formatting elsewhere",
+			local:  base,
+			remote: "# Plan\n\n- one\n- two\n\n1. first\n2. second\n\n*note* here\n\nAdded on Notion\n",
+			want:   base + "\nAdded on Notion\n",
+		},
+		{
+			name:   "edits on both sides of different lines",
+			local:  "# Plan\n\n* one edited\n* two\n\n3) first\n4) second\n\n_note_ here\n",
+			remote: "# Plan\n\n- one\n- two\n\n1. first\n2. second changed\n\n*note* here\n",
+			want:   "# Plan\n\n* one edited\n* two\n\n3) first\n2. second changed\n\n_note_ here\n",
+		},
+		{
+			name:          "edits to the same line conflict",
+			local:         "# Plan\n\n* one\n* two local\n\n3) first\n4) second\n\n_note_ here\n",
+			remote:        "# Plan\n\n- one\n- two remote\n\n1. first\n2. second\n\n*note* here\n",
+			want:          "# Plan\n\n* one\n<<<<<<< local\n* two local\n=======\n- two remote\n>>>>>>> notion\n\n3) first\n4) second\n\n_note_ here\n",
+			wantConflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts := Merge3Rebased(base, tt.local, rendered, tt.remote, "local", "notion")
+			if conflicts != tt.wantConflicts {
+				t.Errorf("Merge3Rebased() conflicts = %d, want %d", conflicts, tt.wantConflicts)
+			}
+			if got != tt.want {
+				t.Errorf("Merge3Rebased():\ngot:  %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	bulletRe      = regexp.MustCompile(`^(\s*)[*+] `)
+	orderedRe     = regexp.MustCompile(`^(\s*)(\d+)\) `)
+	listItemRe    = regexp.MustCompile(`^( +)(- |\d+\. )`)
+	blankLinesRe  = regexp.MustCompile(`\n{3,}`)
+	codeFenceLine = regexp.MustCompile("^\\s*(```|~~~)")
+)
+
+// NormalizeMarkdown rewrites Markdown into a canonical form so that local files
+// and Notion content can be compared without noise from equivalent syntax:
+// line endings, trailing whitespace, bullet and ordered list markers, space
+// indentation of nested lists (Notion nests with tabs) and runs of blank lines.
+func NormalizeMarkdown(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	lines := strings.Split(s, "\n")
+
+	inFence := false
+	fenced := make([]bool, len(lines))
+	for i, line := range lines {
+		if codeFenceLine.MatchString(line) {
+			inFence = !inFence
+			lines[i] = strings.TrimRight(line, " \t")
+			fenced[i] = true
+			continue
+		}
+		if inFence {
+			fenced[i] = true
+			continue
+		}
+		line = strings.TrimRight(line, " \t")
+		line = bulletRe.ReplaceAllString(line, "$1- ")
+		lines[i] = orderedRe.ReplaceAllString(line, "$1$2. ")
+	}
+
+	// Use the smallest list indent as one nesting level.
+	width := 0
+	for i, line := range lines {
+		if m := listItemRe.FindStringSubmatch(line); m != nil && !fenced[i] {
+			if width == 0 || len(m[1]) < width {
+				width = len(m[1])
+			}
+		}
+	}
+	if width > 0 {
+		for i, line := range lines {
+			if m := listItemRe.FindStringSubmatch(line); m != nil && !fenced[i] {
+				lines[i] = strings.Repeat("\t", len(m[1])/width) + line[len(m[1]):]
+			}
+		}
+	}
+
+	s = strings.Join(lines, "\n")
+	s = blankLinesRe.ReplaceAllString(s, "\n\n")
+	return strings.Trim(s, "\n")
+}
@@ -0,0 +1,27 @@
+package cli
+
+import "testing"
+
+func TestNormalizeMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"line endings and trailing space", "# Title  \r\n\r\nBody\r\n", "# Title\n\nBody"},
+		{"bullets", "* one\n+ two\n- three", "- one\n- two\n- three"},
+		{"ordered parens", "1) one\n2) two", "1. one\n2. two"},
+		{"nested two spaces", "- a\n  - b\n    - c", "- a\n\t- b\n\t\t- c"},
+		{"nested four spaces", "- a\n    - b", "- a\n\t- b"},
+		{"blank lines collapse", "a\n\n\n\nb", "a\n\nb"},
+		{"code fences untouched", "```\n* keep  \n  - keep\n```", "```\n* keep  \n  - keep\n```"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeMarkdown(tt.input); got != tt.want {
+				t.Errorf("NormalizeMarkdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,364 @@
+package cli
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lox/notion-cli/internal/output"
+)
+
+var (
+	quoteLineRe     = regexp.MustCompile(`^ {0,3}> ?`)
+	alertRe         = regexp.MustCompile(`^\[!(\w+)\]\s*(.*)$`)
+	taskItemRe      = regexp.MustCompile(`^(\s*)[-*+] \[([ xX])\]( |$)`)
+	tableDelimRe    = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	equationLineRe  = regexp.MustCompile(`^\s*\$\$(.+)\$\$\s*$`)
+	codeSpanRe      = regexp.MustCompile("`+[^`]*`+")
+	summaryLineRe   = regexp.MustCompile(`^\s*<summary>(.*)</summary>\s*$`)
+	detailsOpenerRe = regexp.MustCompile(`^\s*(<details\b[^>]*>)\s*(.*)$`)
+)
+
+// MarkdownToNotion converts GitHub-flavoured Markdown to Notion-flavoured
+// Markdown before it is uploaded. Alerts (> [!NOTE]) and quotes that start
+// with an emoji become callouts, <details> become toggles, task lists become
+// to-dos, tables become <table> blocks and $x$ becomes an inline equation.
+// The children of <callout>, <details> and <columns> are indented the way
+// Notion expects. It is the inverse of output.NotionToMarkdown, so content
+// pulled back from Notion reads the same as the file it came from.
+func MarkdownToNotion(markdown string) string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	return strings.Join(convertBlocks(lines), "\n")
+}
+
+func convertBlocks(lines []string) []string {
+	var out []string
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case codeFenceLine.MatchString(line):
+			end := closingLine(lines, i, codeFenceLine.MatchString)
+			out = append(out, lines[i:end]...)
+			i = end
+		case strings.TrimSpace(line) == "$$":
+			end := closingLine(lines, i, func(l string) bool { return strings.TrimSpace(l) == "$$" })
+			out = append(out, lines[i:end]...)
+			i = end
+		case equationLineRe.MatchString(line):
+			out = append(out, "$$", strings.TrimSpace(equationLineRe.FindStringSubmatch(line)[1]), "$$")
+			i++
+		case quoteLineRe.MatchString(line):
+			end := i + 1
+			for end < len(lines) && quoteLineRe.MatchString(lines[end]) {
+				end++
+			}
+			out = append(out, convertQuote(lines[i:end])...)
+			i = end
+		case blockTagRe.MatchString(line):
+			end := closingTag(lines, i)
+			out = append(out, convertContainer(lines[i:end])...)
+			i = end
+		case i+1 < len(lines) && strings.Contains(line, "|") && strings.Contains(lines[i+1], "|") && tableDelimRe.MatchString(lines[i+1]):
+			end := i + 2
+			for end < len(lines) && strings.Contains(lines[end], "|") && strings.TrimSpace(lines[end]) != "" {
+				end++
+			}
+			out = append(out, convertTable(line, lines[i+2:end])...)
+			i = end
+		default:
+			out = append(out, convertInline(line))
+			i++
+		}
+	}
+	return out
+}
+
+// closingLine returns the index just past the line that closes the block
+// opened at lines[start], or len(lines) if it is never closed.
+func closingLine(lines []string, start int, closes func(string) bool) int {
+	for i := start + 1; i < len(lines); i++ {
+		if closes(lines[i]) {
+			return i + 1
+		}
+	}
+	return len(lines)
+}
+
+// closingTag returns the index just past the line that closes the tag block
+// opened at lines[start], counting nested blocks.
+func closingTag(lines []string, start int) int {
+	m := blockTagRe.FindStringSubmatch(lines[start])
+	if m[1] == "/" || m[3] == "/" || strings.Contains(lines[start], "</"+m[2]+">") {
+		return start + 1
+	}
+	depth := 0
+	for i := start; i < len(lines); i++ {
+		if codeFenceLine.MatchString(lines[i]) {
+			i = closingLine(lines, i, codeFenceLine.MatchString) - 1
+			continue
+		}
+		t := blockTagRe.FindStringSubmatch(lines[i])
+		if t == nil || t[2] != m[2] || t[3] == "/" {
+			continue
+		}
+		if t[1] == "/" {
+			depth--
+		} else if !strings.Contains(lines[i], "</"+t[2]+">") {
+			depth++
+		}
+		if depth == 0 {
+			return i + 1
+		}
+	}
+	return len(lines)
+}
+
+// convertQuote turns a GitHub alert, or a quote starting with an emoji, into
+// a callout. Other quotes are kept.
+func convertQuote(block []string) []string {
+	inner := make([]string, len(block))
+	for i, line := range block {
+		inner[i] = quoteLineRe.ReplaceAllString(line, "")
+	}
+
+	first := strings.TrimSpace(inner[0])
+	var open string
+	if m := alertRe.FindStringSubmatch(first); m != nil {
+		for _, a := range output.Admonitions {
+			if strings.EqualFold(a.Type, m[1]) {
+				open = `<callout icon="` + a.Icon + `" color="` + a.Color + `">`
+				inner[0] = m[2]
+				break
+			}
+		}
+	} else if r, _ := utf8.DecodeRuneInString(first); IsEmoji(r) {
+		icon, rest, _ := strings.Cut(first, " ")
+		open = `<callout icon="` + icon + `">`
+		inner[0] = strings.TrimSpace(rest)
+	}
+
+	if open == "" {
+		out := make([]string, len(block))
+		for i, line := range block {
+			out[i] = convertInline(line)
+		}
+		return out
+	}
+	if inner[0] == "" {
+		inner = inner[1:]
+	}
+	body := indentLines(convertBlocks(trimBlankLines(inner)), "\t")
+	return append(append([]string{open}, body...), "</callout>")
+}
+
+// convertContainer indents the children of a <callout>, <details> or
+// <columns> block written as HTML. Other tag blocks are passed through.
+func convertContainer(block []string) []string {
+	m := blockTagRe.FindStringSubmatch(block[0])
+	if len(block) < 2 || m[1] == "/" || !strings.HasPrefix(strings.TrimSpace(block[len(block)-1]), "</"+m[2]+">") {
+		return block
+	}
+	open := strings.TrimSpace(block[0])
+	inner := dedentLines(block[1 : len(block)-1])
+	closeTag := "</" + m[2] + ">"
+
+	switch m[2] {
+	case "callout":
+		body := indentLines(convertBlocks(trimBlankLines(inner)), "\t")
+		return append(append([]string{open}, body...), closeTag)
+
+	case "details":
+		var summary string
+		if d := detailsOpenerRe.FindStringSubmatch(open); d != nil && d[2] != "" {
+			open, summary = d[1], d[2]
+		} else {
+			inner = trimBlankLines(inner)
+			if len(inner) > 0 && summaryLineRe.MatchString(inner[0]) {
+				summary, inner = strings.TrimSpace(inner[0]), inner[1:]
+			}
+		}
+		out := []string{open}
+		if summary != "" {
+			out = append(out, summary)
+		}
+		out = append(out, indentLines(convertBlocks(trimBlankLines(inner)), "\t")...)
+		return append(out, closeTag)
+
+	case "columns":
+		out := []string{open}
+		for i := 0; i < len(inner); {
+			t := blockTagRe.FindStringSubmatch(inner[i])
+			if t == nil || t[2] != "column" || t[1] == "/" {
+				if strings.TrimSpace(inner[i]) != "" {
+					out = append(out, "\t"+inner[i])
+				}
+				i++
+				continue
+			}
+			end := closingTag(inner, i)
+			column := inner[i:end]
+			out = append(out, "\t"+strings.TrimSpace(column[0]))
+			if len(column) > 1 {
+				body := dedentLines(column[1 : len(column)-1])
+				out = append(out, indentLines(convertBlocks(trimBlankLines(body)), "\t\t")...)
+				out = append(out, "\t"+strings.TrimSpace(column[len(column)-1]))
+			}
+			i = end
+		}
+		return append(out, closeTag)
+	}
+	return block
+}
+
+// convertTable turns a GitHub table into a Notion <table> whose first row is
+// the header. A header with only empty cells is dropped and the table gets
+// no header row. Rows are padded or cut to the header's width.
+func convertTable(header string, rows []string) []string {
+	cells := splitTableRow(header)
+	out := []string{`<table header-row="true">`}
+	if strings.TrimSpace(strings.Join(cells, "")) == "" {
+		out[0] = `<table header-row="false">`
+	} else {
+		rows = append([]string{header}, rows...)
+	}
+	for _, row := range rows {
+		values := splitTableRow(row)
+		out = append(out, "\t<tr>")
+		for i := range cells {
+			value := ""
+			if i < len(values) {
+				value = values[i]
+			}
+			out = append(out, "\t\t<td>"+convertInline(value)+"</td>")
+		}
+		out = append(out, "\t</tr>")
+	}
+	return append(out, "</table>")
+}
+
+// splitTableRow splits a table row into its trimmed cells, unescaping \|.
+func splitTableRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row) && row[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case row[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(row[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// convertInline rewrites task list markers as Notion to-dos and $x$ as
+// inline equations, leaving code spans alone.
+func convertInline(line string) string {
+	line = taskItemRe.ReplaceAllStringFunc(line, func(s string) string {
+		m := taskItemRe.FindStringSubmatch(s)
+		return m[1] + "- [" + strings.ToLower(m[2]) + "]" + m[3]
+	})
+
+	var out strings.Builder
+	last := 0
+	for _, span := range codeSpanRe.FindAllStringIndex(line, -1) {
+		out.WriteString(inlineMath(line[last:span[0]]))
+		out.WriteString(line[span[0]:span[1]])
+		last = span[1]
+	}
+	out.WriteString(inlineMath(line[last:]))
+	return out.String()
+}
+
+// inlineMath wraps $x$ as $`x`$. Like GitHub, it needs the text inside the
+// dollars to start and end with a non-space, and the closing dollar not to be
+// followed by a digit, so prices such as $5 and $10 are left alone.
+func inlineMath(s string) string {
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '$' && (i == 0 || !isMathBoundary(s[i-1])) && i+2 < len(s) && s[i+1] != ' ' && s[i+1] != '$' {
+			if j := closingDollar(s, i+2); j > 0 {
+				out.WriteString("$`" + s[i+1:j] + "`$")
+				i = j
+				continue
+			}
+		}
+		out.WriteByte(s[i])
+	}
+	return out.String()
+}
+
+func closingDollar(s string, from int) int {
+	for j := from; j < len(s); j++ {
+		if s[j] != '$' || s[j-1] == ' ' || s[j-1] == '\\' {
+			continue
+		}
+		if j+1 < len(s) && (isMathBoundary(s[j+1]) || s[j+1] >= '0' && s[j+1] <= '9') {
+			continue
+		}
+		return j
+	}
+	return -1
+}
+
+// isMathBoundary reports whether a dollar next to c is part of a word rather
+// than the edge of an equation.
+func isMathBoundary(c byte) bool {
+	return c == '$' || c == '\\' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// indentLines prefixes each non-blank line with prefix.
+func indentLines(lines []string, prefix string) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			out[i] = prefix + line
+		}
+	}
+	return out
+}
+
+// dedentLines removes the leading whitespace common to all non-blank lines.
+func dedentLines(lines []string) []string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == -1 || n < indent {
+			indent = n
+		}
+	}
+	if indent <= 0 {
+		return lines
+	}
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) >= indent {
+			out[i] = line[indent:]
+		}
+	}
+	return out
+}
+
+// trimBlankLines drops blank lines at the start and end of lines.
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/lox/notion-cli/internal/output"
+)
+
+func TestMarkdownToNotion(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			"alert",
+			"> [!NOTE]\n> Read this first.",
+			"<callout icon=\"ℹ️\" color=\"blue_bg\">\n\tRead this first.\n</callout>",
+		},
+		{
+			"alert lower case with text on marker line",
+			"> [!warning] Careful",
+			"<callout icon=\"⚠️\" color=\"yellow_bg\">\n\tCareful\n</callout>",
+		},
+		{
+			"emoji quote",
+			"> 🚀 Shipped in v2.",
+			"<callout icon=\"🚀\">\n\tShipped in v2.\n</callout>",
+		},
+		{
+			"plain quote kept",
+			"> Just a quote\n> over two lines",
+			"> Just a quote\n> over two lines",
+		},
+		{
+			"unknown alert kept",
+			"> [!TODO] later",
+			"> [!TODO] later",
+		},
+		{
+			"details",
+			"<details>\n<summary>More</summary>\n\nHidden text.\n\n</details>",
+			"<details>\n<summary>More</summary>\n\tHidden text.\n</details>",
+		},
+		{
+			"details summary on opening line",
+			"<details><summary>More</summary>\n  Hidden\n</details>",
+			"<details>\n<summary>More</summary>\n\tHidden\n</details>",
+		},
+		{
+			"task list",
+			"* [ ] Write docs\n+ [X] Ship it\n  - [x] nested",
+			"- [ ] Write docs\n- [x] Ship it\n  - [x] nested",
+		},
+		{
+			"table",
+			"| Name | Note |\n|:-----|-----:|\n| Ada | a \\| b |\n| Bob |",
+			"<table header-row=\"true\">\n\t<tr>\n\t\t<td>Name</td>\n\t\t<td>Note</td>\n\t</tr>\n\t<tr>\n\t\t<td>Ada</td>\n\t\t<td>a | b</td>\n\t</tr>\n\t<tr>\n\t\t<td>Bob</td>\n\t\t<td></td>\n\t</tr>\n</table>",
+		},
+		{
+			"inline math",
+			"Euler: $e^{i\\pi} + 1 = 0$ and $x$.",
+			"Euler: $`e^{i\\pi} + 1 = 0`$ and $`x`$.",
+		},
+		{
+			"prices are not math",
+			"It costs $5 or $10, not US$3.",
+			"It costs $5 or $10, not US$3.",
+		},
+		{
+			"math in code left alone",
+			"Run `echo $x$` now",
+			"Run `echo $x$` now",
+		},
+		{
+			"single line block equation",
+			"$$E = mc^2$$",
+			"$$\nE = mc^2\n$$",
+		},
+		{
+			"code fences untouched",
+			"```\n> [!NOTE]\n| a | b |\n|---|---|\n```",
+			"```\n> [!NOTE]\n| a | b |\n|---|---|\n```",
+		},
+		{
+			"columns",
+			"<columns>\n<column>\n\nLeft\n\n</column>\n<column>\n\n- [ ] Right\n\n</column>\n</columns>",
+			"<columns>\n\t<column>\n\t\tLeft\n\t</column>\n\t<column>\n\t\t- [ ] Right\n\t</column>\n</columns>",
+		},
+		{
+			"table inside alert",
+			"> [!TIP]\n> | a | b |\n> | - | - |\n> | 1 | 2 |",
+			"<callout icon=\"💡\" color=\"green_bg\">\n\t<table header-row=\"true\">\n\t\t<tr>\n\t\t\t<td>a</td>\n\t\t\t<td>b</td>\n\t\t</tr>\n\t\t<tr>\n\t\t\t<td>1</td>\n\t\t\t<td>2</td>\n\t\t</tr>\n\t</table>\n</callout>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MarkdownToNotion(tt.input); got != tt.want {
+				t.Errorf("MarkdownToNotion() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownToNotionRoundTrip(t *testing.T) {
+	docs := map[string]string{
+		"alert":            "> [!NOTE]\n> Read this first.\n>\n> Second paragraph.",
+		"every alert":      "> [!TIP]\n> a\n\n> [!IMPORTANT]\n> b\n\n> [!WARNING]\n> c\n\n> [!CAUTION]\n> d",
+		"emoji quote":      "> 🚀 Shipped in v2.",
+		"details":          "<details>\n<summary>More **info**</summary>\n\nHidden text.\n\n- a list\n\n</details>",
+		"task list":        "- [ ] Write docs\n- [x] Ship it",
+		"table":            "| Name | Role |\n| --- | --- |\n| Ada | **Eng** |\n| Bob | a \\| b |",
+		"headerless table": "|  |  |\n| --- | --- |\n| Ada | Eng |\n| Bob | Ops |",
+		"math":             "Euler: $e^{i\\pi} + 1 = 0$\n\n$$\nx^2 + y^2\n$$",
+		"columns":          "<columns>\n<column>\n\n## Left\n\nText\n\n</column>\n<column>\n\n- [ ] Right\n\n</column>\n</columns>",
+		"nested table":     "> [!TIP]\n> Totals:\n>\n> | a | b |\n> | --- | --- |\n> | 1 | 2 |",
+		"mixed": "# Release\n\nIntro with $x$.\n\n> [!WARNING]\n> Back up first.\n\n" +
+			"<details>\n<summary>Steps</summary>\n\n1. Stop\n2. Start\n\n</details>\n\n| Step | Done |\n| --- | --- |\n| 1 | yes |",
+	}
+
+	for name, doc := range docs {
+		t.Run(name, func(t *testing.T) {
+			notion := MarkdownToNotion(doc)
+			if got := output.NotionToMarkdown(notion); got != doc {
+				t.Errorf("round trip =\n%s\nwant\n%s\nvia\n%s", got, doc, notion)
+			}
+		})
+	}
+}
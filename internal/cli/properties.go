@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lox/notion-cli/internal/mcp"
+	"github.com/lox/notion-cli/internal/output"
+)
+
+// ParsePropertyArgs parses repeated Key=Value flags into a map. Keys may use the
+// expanded date syntax, e.g. date:Due:start=2026-03-01.
+func ParsePropertyArgs(props []string) (map[string]string, error) {
+	properties := make(map[string]string, len(props))
+	for _, p := range props {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, &output.UserError{Message: "invalid property format (expected key=value): " + p}
+		}
+		properties[strings.TrimSpace(k)] = v
+	}
+	return properties, nil
+}
+
+// CoerceProperties converts string property values into the form the Notion
+// MCP server expects for each property's type in the schema. A nil schema
+// passes values through unchanged.
+func CoerceProperties(schema map[string]mcp.PropertySchema, raw map[string]string) (map[string]any, error) {
+	props := make(map[string]any, len(raw))
+	for key, value := range raw {
+		if schema == nil || isExpandedKey(key) {
+			props[key] = value
+			continue
+		}
+
+		prop, ok := lookupProperty(schema, key)
+		if !ok {
+			return nil, unknownPropertyError(schema, key)
+		}
+
+		if err := coerceProperty(props, prop, value); err != nil {
+			return nil, err
+		}
+	}
+	return props, nil
+}
+
+// CoerceKnownProperties is CoerceProperties for the keys the schema has.
+// Other keys are passed through unchanged, leaving the server to accept or
+// reject them.
+func CoerceKnownProperties(schema map[string]mcp.PropertySchema, raw map[string]string) (map[string]any, error) {
+	known := make(map[string]string, len(raw))
+	props := make(map[string]any, len(raw))
+	for key, value := range raw {
+		if _, ok := lookupProperty(schema, key); ok || schema == nil || isExpandedKey(key) {
+			known[key] = value
+		} else {
+			props[key] = value
+		}
+	}
+	coerced, err := CoerceProperties(schema, known)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range coerced {
+		props[key] = value
+	}
+	return props, nil
+}
+
+// FrontmatterProperties converts frontmatter into properties for a page whose
+// database has the given schema; a nil schema means the page isn't in a
+// database, so only the title applies. Keys that don't name a property are
+// returned as ignored rather than failing, since frontmatter often carries
+// keys meant for other tools.
+func FrontmatterProperties(schema map[string]mcp.PropertySchema, fm Frontmatter) (map[string]any, []string, error) {
+	raw := map[string]string{}
+	var ignored []string
+	for key, value := range fm.Properties {
+		if _, ok := lookupProperty(schema, key); schema != nil && (ok || isExpandedKey(key)) {
+			raw[key] = value
+			continue
+		}
+		ignored = append(ignored, key)
+	}
+	sort.Strings(ignored)
+
+	props, err := CoerceProperties(schema, raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fm.Title != "" {
+		props[TitlePropertyKey(schema)] = fm.Title
+	}
+	return props, ignored, nil
+}
+
+// UnsetProperties returns null values that clear the named properties.
+func UnsetProperties(schema map[string]mcp.PropertySchema, keys []string) (map[string]any, error) {
+	props := make(map[string]any, len(keys))
+	for _, key := range keys {
+		if schema == nil || isExpandedKey(key) {
+			props[key] = nil
+			continue
+		}
+		prop, ok := lookupProperty(schema, key)
+		if !ok {
+			return nil, unknownPropertyError(schema, key)
+		}
+		switch prop.Type {
+		case "title":
+			return nil, &output.UserError{Message: "cannot unset the title property: " + prop.Name}
+		case "date":
+			props["date:"+prop.Name+":start"] = nil
+		default:
+			props[propertyKey(prop.Name)] = nil
+		}
+	}
+	return props, nil
+}
+
+// PageSchema returns the property schema of the database a fetched page lives
+// in, or nil if its parent is not a database.
+func PageSchema(ctx context.Context, client *mcp.Client, page *mcp.FetchResult) (map[string]mcp.PropertySchema, error) {
+	switch page.ParentType {
+	case "data-source":
+		return client.FetchSchema(ctx, "collection://"+page.ParentID)
+	case "database":
+		return client.FetchSchema(ctx, page.ParentID)
+	}
+	return nil, nil
+}
+
+// TitlePropertyName returns the name of the title property in the schema,
+// or "title" for pages outside a database.
+func TitlePropertyName(schema map[string]mcp.PropertySchema) string {
+	for _, prop := range schema {
+		if prop.Type == "title" {
+			return prop.Name
+		}
+	}
+	return "title"
+}
+
+// TitlePropertyKey returns the key to send the title under, which differs
+// from its name when the title property is called "ID" or "URL".
+func TitlePropertyKey(schema map[string]mcp.PropertySchema) string {
+	return propertyKey(TitlePropertyName(schema))
+}
+
+func coerceProperty(props map[string]any, prop mcp.PropertySchema, value string) error {
+	key := propertyKey(prop.Name)
+
+	switch prop.Type {
+	case "number":
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return &output.UserError{Message: fmt.Sprintf("property %q expects a number, got %q", prop.Name, value)}
+		}
+		props[key] = n
+	case "checkbox":
+		b, err := parseCheckbox(value)
+		if err != nil {
+			return &output.UserError{Message: fmt.Sprintf("property %q expects true or false, got %q", prop.Name, value)}
+		}
+		if b {
+			props[key] = "__YES__"
+		} else {
+			props[key] = "__NO__"
+		}
+	case "date":
+		start, end, _ := strings.Cut(value, "/")
+		props["date:"+prop.Name+":start"] = strings.TrimSpace(start)
+		if end != "" {
+			props["date:"+prop.Name+":end"] = strings.TrimSpace(end)
+		}
+		props["date:"+prop.Name+":is_datetime"] = boolToInt(strings.Contains(start, "T"))
+	case "select", "status":
+		name, ok := matchOption(prop, value)
+		if !ok && prop.Type == "status" {
+			return &output.UserError{Message: fmt.Sprintf("invalid status %q for %q (options: %s)", value, prop.Name, optionNames(prop))}
+		}
+		props[key] = name
+	case "multi_select":
+		var names []string
+		for _, v := range strings.Split(value, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+			name, _ := matchOption(prop, v)
+			names = append(names, name)
+		}
+		encoded, err := json.Marshal(names)
+		if err != nil {
+			return err
+		}
+		props[key] = string(encoded)
+	default:
+		props[key] = value
+	}
+	return nil
+}
+
+// propertyKey returns the key to send for a property. The MCP server reserves
+// "id" and "url", so user-defined properties with those names are prefixed.
+func propertyKey(name string) string {
+	switch strings.ToLower(name) {
+	case "id", "url":
+		return "userDefined:" + name
+	}
+	return name
+}
+
+func isExpandedKey(key string) bool {
+	return strings.HasPrefix(key, "date:") || strings.HasPrefix(key, "place:") || strings.HasPrefix(key, "userDefined:")
+}
+
+func lookupProperty(schema map[string]mcp.PropertySchema, key string) (mcp.PropertySchema, bool) {
+	if prop, ok := schema[key]; ok {
+		return prop, true
+	}
+	for name, prop := range schema {
+		if strings.EqualFold(name, key) {
+			return prop, true
+		}
+	}
+	return mcp.PropertySchema{}, false
+}
+
+func matchOption(prop mcp.PropertySchema, value string) (string, bool) {
+	for _, opt := range prop.Options {
+		if strings.EqualFold(opt.Name, value) {
+			return opt.Name, true
+		}
+	}
+	return value, false
+}
+
+func optionNames(prop mcp.PropertySchema) string {
+	names := make([]string, 0, len(prop.Options))
+	for _, opt := range prop.Options {
+		names = append(names, opt.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func parseCheckbox(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "1", "x", "on", "checked":
+		return true, nil
+	case "false", "no", "n", "0", "", "off", "unchecked":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid checkbox value: %s", value)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func unknownPropertyError(schema map[string]mcp.PropertySchema, key string) error {
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &output.UserError{Message: fmt.Sprintf("unknown property %q (available: %s)", key, strings.Join(names, ", "))}
+}
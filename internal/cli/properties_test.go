@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lox/notion-cli/internal/mcp"
+)
+
+var testSchema = map[string]mcp.PropertySchema{
+	"Name":     {Name: "Name", Type: "title"},
+	"Status":   {Name: "Status", Type: "status", Options: []mcp.PropertyOption{{Name: "Not started"}, {Name: "Done"}}},
+	"Points":   {Name: "Points", Type: "number"},
+	"Blocked":  {Name: "Blocked", Type: "checkbox"},
+	"Tags":     {Name: "Tags", Type: "multi_select", Options: []mcp.PropertyOption{{Name: "Backend"}}},
+	"Due":      {Name: "Due", Type: "date"},
+	"URL":      {Name: "URL", Type: "url"},
+	"Priority": {Name: "Priority", Type: "select", Options: []mcp.PropertyOption{{Name: "High"}}},
+}
+
+func TestCoerceProperties(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     map[string]string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name: "status matches option case-insensitively",
+			raw:  map[string]string{"status": "done"},
+			want: map[string]any{"Status": "Done"},
+		},
+		{
+			name:    "unknown status option",
+			raw:     map[string]string{"Status": "Shipped"},
+			wantErr: true,
+		},
+		{
+			name: "number",
+			raw:  map[string]string{"Points": "3.5"},
+			want: map[string]any{"Points": 3.5},
+		},
+		{
+			name:    "invalid number",
+			raw:     map[string]string{"Points": "lots"},
+			wantErr: true,
+		},
+		{
+			name: "checkbox",
+			raw:  map[string]string{"Blocked": "yes"},
+			want: map[string]any{"Blocked": "__YES__"},
+		},
+		{
+			name: "multi select",
+			raw:  map[string]string{"Tags": "backend, Infra"},
+			want: map[string]any{"Tags": `["Backend","Infra"]`},
+		},
+		{
+			name: "date expands",
+			raw:  map[string]string{"Due": "2026-03-01"},
+			want: map[string]any{"date:Due:start": "2026-03-01", "date:Due:is_datetime": 0},
+		},
+		{
+			name: "expanded date key passes through",
+			raw:  map[string]string{"date:Due:start": "2026-03-01"},
+			want: map[string]any{"date:Due:start": "2026-03-01"},
+		},
+		{
+			name: "reserved name is prefixed",
+			raw:  map[string]string{"URL": "https://example.com"},
+			want: map[string]any{"userDefined:URL": "https://example.com"},
+		},
+		{
+			name: "select allows new options",
+			raw:  map[string]string{"Priority": "Low"},
+			want: map[string]any{"Priority": "Low"},
+		},
+		{
+			name:    "unknown property",
+			raw:     map[string]string{"Owner": "me"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CoerceProperties(testSchema, tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("CoerceProperties() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CoerceProperties() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CoerceProperties() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTitlePropertyKey(t *testing.T) {
+	if got := TitlePropertyKey(testSchema); got != "Name" {
+		t.Errorf("TitlePropertyKey() = %q, want %q", got, "Name")
+	}
+	if got := TitlePropertyKey(nil); got != "title" {
+		t.Errorf("TitlePropertyKey(nil) = %q, want %q", got, "title")
+	}
+	schema := map[string]mcp.PropertySchema{"ID": {Name: "ID", Type: "title"}}
+	if got := TitlePropertyKey(schema); got != "userDefined:ID" {
+		t.Errorf("TitlePropertyKey() = %q, want %q", got, "userDefined:ID")
+	}
+}
+
+func TestCoerceKnownProperties(t *testing.T) {
+	got, err := CoerceKnownProperties(testSchema, map[string]string{"status": "done", "Owner": "me"})
+	if err != nil {
+		t.Fatalf("CoerceKnownProperties() error = %v", err)
+	}
+	want := map[string]any{"Status": "Done", "Owner": "me"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CoerceKnownProperties() = %#v, want %#v", got, want)
+	}
+
+	if _, err := CoerceKnownProperties(testSchema, map[string]string{"Points": "lots"}); err == nil {
+		t.Error("CoerceKnownProperties() with an invalid number succeeded, want error")
+	}
+}
+
+func TestUnsetProperties(t *testing.T) {
+	got, err := UnsetProperties(testSchema, []string{"Due", "tags"})
+	if err != nil {
+		t.Fatalf("UnsetProperties() error = %v", err)
+	}
+	want := map[string]any{"date:Due:start": nil, "Tags": nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnsetProperties() = %#v, want %#v", got, want)
+	}
+
+	if _, err := UnsetProperties(testSchema, []string{"Name"}); err == nil {
+		t.Error("UnsetProperties() on title should fail")
+	}
+}
+
+func TestParsePropertyArgs(t *testing.T) {
+	got, err := ParsePropertyArgs([]string{"Status=Done", "date:Due:start=2026-03-01", "Note=a=b"})
+	if err != nil {
+		t.Fatalf("ParsePropertyArgs() error = %v", err)
+	}
+	want := map[string]string{"Status": "Done", "date:Due:start": "2026-03-01", "Note": "a=b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePropertyArgs() = %v, want %v", got, want)
+	}
+
+	if _, err := ParsePropertyArgs([]string{"novalue"}); err == nil {
+		t.Error("ParsePropertyArgs() should reject missing =")
+	}
+}
+
+func TestFrontmatterProperties(t *testing.T) {
+	fm, _ := ParseFrontmatter("---\ntitle: Launch plan\nstatus: Done\ntags: [backend, Frontend]\nblocked: true\ndue: 2026-03-01\nauthor: sam\n---\n\nBody\n")
+
+	props, ignored, err := FrontmatterProperties(testSchema, fm)
+	if err != nil {
+		t.Fatalf("FrontmatterProperties() error = %v", err)
+	}
+	want := map[string]any{
+		"Name":                 "Launch plan",
+		"Status":               "Done",
+		"Tags":                 `["Backend","Frontend"]`,
+		"Blocked":              "__YES__",
+		"date:Due:start":       "2026-03-01",
+		"date:Due:is_datetime": 0,
+	}
+	if !reflect.DeepEqual(props, want) {
+		t.Errorf("FrontmatterProperties() = %#v, want %#v", props, want)
+	}
+	if !reflect.DeepEqual(ignored, []string{"author"}) {
+		t.Errorf("ignored = %v, want [author]", ignored)
+	}
+
+	props, ignored, err = FrontmatterProperties(nil, fm)
+	if err != nil {
+		t.Fatalf("FrontmatterProperties(nil) error = %v", err)
+	}
+	if !reflect.DeepEqual(props, map[string]any{"title": "Launch plan"}) {
+		t.Errorf("FrontmatterProperties(nil) = %#v", props)
+	}
+	if len(ignored) != 5 {
+		t.Errorf("FrontmatterProperties(nil) ignored = %v, want all 5 keys", ignored)
+	}
+}
@@ -232,3 +232,53 @@ func resolveDatabaseByName(ctx context.Context, client *mcp.Client, name string)
 func IsEmoji(r rune) bool {
 	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r) && !unicode.IsPunct(r) && r > 127
 }
+
+type ParentKind int
+
+const (
+	ParentPage ParentKind = iota
+	ParentDatabase
+)
+
+// ResolveParentID resolves a reference that may be either a page or a database.
+// Names are looked up as pages first, then as databases. IDs and URLs are fetched
+// to find out what they point at. Database IDs are returned as data source IDs.
+func ResolveParentID(ctx context.Context, client *mcp.Client, input string) (ParentKind, string, error) {
+	ref := ParsePageRef(input)
+	if ref.Kind == RefName {
+		if id, err := resolvePageByName(ctx, client, input); err == nil {
+			return ParentPage, id, nil
+		}
+		dbID, err := resolveDatabaseByName(ctx, client, input)
+		if err != nil {
+			return ParentPage, "", &output.UserError{Message: "no page or database found: " + input}
+		}
+		dsID, err := client.ResolveDataSourceID(ctx, dbID)
+		return ParentDatabase, dsID, err
+	}
+
+	id, err := ResolvePageID(ctx, client, input)
+	if err != nil {
+		return ParentPage, "", err
+	}
+
+	result, err := client.Fetch(ctx, id)
+	if err != nil {
+		return ParentPage, "", err
+	}
+	if isDatabaseFetch(result) {
+		dsID, err := client.ResolveDataSourceID(ctx, id)
+		return ParentDatabase, dsID, err
+	}
+	return ParentPage, id, nil
+}
+
+func isDatabaseFetch(result *mcp.FetchResult) bool {
+	switch result.Type {
+	case "database", "data_source":
+		return true
+	case "page":
+		return false
+	}
+	return strings.Contains(result.Content, "<database") && !strings.Contains(result.Content, "<page")
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Section is the part of a document under a heading: the heading line and
+// everything up to the next heading of the same or a higher level. Start and
+// End are byte offsets into the document, with End excluding the newline
+// that ends the section.
+type Section struct {
+	Heading string
+	Level   int
+	Start   int
+	End     int
+}
+
+// headingRe matches a Markdown heading, capturing its level and text. Notion
+// appends block attributes such as {toggle="true"} to heading lines.
+var headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*(?:\{[^{}]*\})?\s*$`)
+
+// FindSection returns the section whose heading matches name, ignoring case
+// and surrounding whitespace. Headings inside code fences are ignored. It is
+// an error if no heading or more than one heading matches.
+func FindSection(doc, name string) (Section, error) {
+	type heading struct {
+		text  string
+		level int
+		start int
+	}
+
+	var headings []heading
+	inFence := false
+	off := 0
+	for _, line := range strings.SplitAfter(doc, "\n") {
+		start := off
+		off += len(line)
+		trimmed := strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(strings.TrimSpace(trimmed), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(trimmed); m != nil {
+			headings = append(headings, heading{
+				text:  strings.TrimSpace(strings.TrimRight(m[2], "#")),
+				level: len(m[1]),
+				start: start,
+			})
+		}
+	}
+
+	want := strings.TrimSpace(name)
+	found := -1
+	for i, h := range headings {
+		if !strings.EqualFold(h.text, want) {
+			continue
+		}
+		if found >= 0 {
+			return Section{}, fmt.Errorf("more than one section is titled %q", name)
+		}
+		found = i
+	}
+	if found < 0 {
+		return Section{}, fmt.Errorf("no section titled %q", name)
+	}
+
+	h := headings[found]
+	end := len(doc)
+	for _, next := range headings[found+1:] {
+		if next.level <= h.level {
+			end = next.start
+			break
+		}
+	}
+	for end > h.start && (doc[end-1] == '\n' || doc[end-1] == '\r') {
+		end--
+	}
+
+	return Section{Heading: h.text, Level: h.level, Start: h.start, End: end}, nil
+}
+
+// ReplaceSection plans a content edit that replaces the body of a section,
+// keeping its heading. The selection covers the heading line as well, so it
+// stays unambiguous when the body is empty.
+func ReplaceSection(doc string, section Section, body string) (ContentEdit, error) {
+	selection, ok := SelectionFor(doc, section.Start, section.End)
+	if !ok {
+		return ContentEdit{}, fmt.Errorf("could not select section %q unambiguously", section.Heading)
+	}
+
+	headingLine, _, _ := strings.Cut(doc[section.Start:section.End], "\n")
+	newStr := headingLine
+	if body = strings.Trim(body, "\n"); body != "" {
+		newStr += "\n" + body
+	}
+	return ContentEdit{Selection: selection, NewStr: newStr}, nil
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+const runbook = `Intro text.
+# Runbook
+## Deploy steps {toggle="true"}
+1. Build
+### Rollback
+Revert the release.
+` + "```" + `
+# not a heading
+` + "```" + `
+## Status
+Green.
+
+# Appendix
+Links.`
+
+func TestFindSection(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"deploy steps", "## Deploy steps {toggle=\"true\"}\n1. Build\n### Rollback\nRevert the release.\n```\n# not a heading\n```"},
+		{"Rollback", "### Rollback\nRevert the release.\n```\n# not a heading\n```"},
+		{" Status ", "## Status\nGreen."},
+		{"Appendix", "# Appendix\nLinks."},
+	}
+	for _, tt := range tests {
+		s, err := FindSection(runbook, tt.name)
+		if err != nil {
+			t.Fatalf("FindSection(%q) error: %v", tt.name, err)
+		}
+		if got := runbook[s.Start:s.End]; got != tt.want {
+			t.Errorf("FindSection(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := FindSection(runbook, "not a heading"); err == nil {
+		t.Error("FindSection() matched a heading inside a code fence")
+	}
+	if _, err := FindSection("# A\n## B\n# B\n", "b"); err == nil {
+		t.Error("FindSection() accepted an ambiguous heading")
+	}
+}
+
+func TestReplaceSection(t *testing.T) {
+	s, err := FindSection(runbook, "Status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit, err := ReplaceSection(runbook, s, "Degraded.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edit.NewStr != "## Status\nDegraded." {
+		t.Errorf("NewStr = %q", edit.NewStr)
+	}
+	if strings.Count(runbook, edit.Selection) != 1 || !strings.HasPrefix(edit.Selection, "## Status") {
+		t.Errorf("Selection = %q", edit.Selection)
+	}
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SyncSnapshot records both sides of a page as of its last sync, so the next
+// sync can tell which side changed and merge against a common base.
+type SyncSnapshot struct {
+	// Base is the file body (local Markdown) at the last sync.
+	Base string `json:"base"`
+	// Remote is the page body as Notion returned it after the last sync.
+	Remote   string    `json:"remote"`
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+// CacheDir returns the directory for notion-cli's cached state, creating it
+// if needed.
+func CacheDir(sub string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "notion-cli", sub)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func syncSnapshotPath(notionID string) (string, error) {
+	dir, err := CacheDir("sync")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, notionID+".json"), nil
+}
+
+// LoadSyncSnapshot returns the snapshot stored for a page, or false if the
+// page has never been synced from this machine.
+func LoadSyncSnapshot(notionID string) (*SyncSnapshot, bool, error) {
+	path, err := syncSnapshotPath(notionID)
+	if err != nil {
+		return nil, false, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var snap SyncSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, false, fmt.Errorf("parse sync snapshot %s: %w", path, err)
+	}
+	return &snap, true, nil
+}
+
+// SaveSyncSnapshot stores the snapshot for a page.
+func SaveSyncSnapshot(notionID string, snap SyncSnapshot) error {
+	path, err := syncSnapshotPath(notionID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lox/notion-cli/internal/output"
+	"gopkg.in/yaml.v3"
+)
+
+// templateVarRe matches {{name}} placeholders. Names can't contain ":" or
+// "/", so the {{https://...}} URLs in fetched Notion content are not matched.
+var templateVarRe = regexp.MustCompile(`\{\{\s*([A-Za-z_][\w.-]*)\s*\}\}`)
+
+// BuiltinTemplateVars returns the variables every template can use: date
+// (YYYY-MM-DD), time (HH:MM), year, week (the ISO week number) and user.
+func BuiltinTemplateVars(now time.Time, user string) map[string]string {
+	_, week := now.ISOWeek()
+	return map[string]string{
+		"date": now.Format("2006-01-02"),
+		"time": now.Format("15:04"),
+		"year": strconv.Itoa(now.Year()),
+		"week": strconv.Itoa(week),
+		"user": user,
+	}
+}
+
+// ParseTemplateVars parses repeated key=value flags into a map.
+func ParseTemplateVars(vars []string) (map[string]string, error) {
+	values := make(map[string]string, len(vars))
+	for _, v := range vars {
+		k, value, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, &output.UserError{Message: "invalid variable format (expected key=value): " + v}
+		}
+		values[strings.TrimSpace(k)] = value
+	}
+	return values, nil
+}
+
+// TemplateUses reports whether text contains a {{name}} placeholder.
+func TemplateUses(text, name string) bool {
+	for _, m := range templateVarRe.FindAllStringSubmatch(text, -1) {
+		if m[1] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandTemplate replaces {{name}} placeholders in text with their values. It
+// is an error for a placeholder to have no value, so a missing --var doesn't
+// end up in the created page.
+func ExpandTemplate(text string, vars map[string]string) (string, error) {
+	var missing []string
+	seen := map[string]bool{}
+	expanded := templateVarRe.ReplaceAllStringFunc(text, func(match string) string {
+		name := templateVarRe.FindStringSubmatch(match)[1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		if !seen[name] {
+			seen[name] = true
+			missing = append(missing, name)
+		}
+		return match
+	})
+	if len(missing) > 0 {
+		return "", &output.UserError{Message: fmt.Sprintf("template variables without a value: %s (set them with --var)", strings.Join(missing, ", "))}
+	}
+	return expanded, nil
+}
+
+// TemplateVarDefaults returns the vars: block of a template's frontmatter.
+// It is parsed on its own, since the rest of the frontmatter may only become
+// valid YAML once its placeholders are filled in, as in title: {{date}}.
+func TemplateVarDefaults(text string) (map[string]string, error) {
+	var block []string
+	inVars := false
+	for _, line := range strings.Split(extractFrontmatterBlock(text), "\n") {
+		indented := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+		switch {
+		case strings.HasPrefix(line, FrontmatterVars+":"):
+			inVars = true
+		case inVars && !indented && strings.TrimSpace(line) != "":
+			inVars = false
+		}
+		if inVars {
+			block = append(block, line)
+		}
+	}
+	if len(block) == 0 {
+		return nil, nil
+	}
+
+	var values map[string]any
+	if err := yaml.Unmarshal([]byte(strings.Join(block, "\n")), &values); err != nil {
+		return nil, fmt.Errorf("template vars: %w", err)
+	}
+	vars, ok := values[FrontmatterVars].(map[string]any)
+	if !ok && values[FrontmatterVars] != nil {
+		return nil, &output.UserError{Message: "template vars must be a map of names to default values"}
+	}
+	defaults := make(map[string]string, len(vars))
+	for name, value := range vars {
+		if s, ok := PropertyString(value); ok {
+			defaults[name] = s
+		}
+	}
+	return defaults, nil
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuiltinTemplateVars(t *testing.T) {
+	vars := BuiltinTemplateVars(time.Date(2026, 1, 1, 9, 30, 0, 0, time.UTC), "Ada")
+	want := map[string]string{
+		"date": "2026-01-01",
+		"time": "09:30",
+		"year": "2026",
+		"week": "1",
+		"user": "Ada",
+	}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("%s = %q, want %q", k, vars[k], v)
+		}
+	}
+}
+
+func TestExpandTemplate(t *testing.T) {
+	text := "# Incident {{ date }}: {{summary}}\n" +
+		`<page url="{{https://www.notion.so/abc}}">Runbook</page>` + "\n" +
+		"Owner: {{user}}"
+
+	got, err := ExpandTemplate(text, map[string]string{"date": "2026-03-01", "summary": "API down", "user": "Ada"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Incident 2026-03-01: API down\n" +
+		`<page url="{{https://www.notion.so/abc}}">Runbook</page>` + "\n" +
+		"Owner: Ada"
+	if got != want {
+		t.Errorf("ExpandTemplate() = %q, want %q", got, want)
+	}
+
+	if _, err := ExpandTemplate(text, map[string]string{"date": "x"}); err == nil ||
+		err.Error() != "template variables without a value: summary, user (set them with --var)" {
+		t.Errorf("ExpandTemplate() error = %v", err)
+	}
+}
+
+func TestParseTemplateVars(t *testing.T) {
+	vars, err := ParseTemplateVars([]string{"severity=SEV2", " team =a=b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars["severity"] != "SEV2" || vars["team"] != "a=b" {
+		t.Errorf("ParseTemplateVars() = %v", vars)
+	}
+	if _, err := ParseTemplateVars([]string{"novalue"}); err == nil {
+		t.Error("ParseTemplateVars() accepted a variable without =")
+	}
+}
+
+func TestTemplateVarDefaults(t *testing.T) {
+	text := "---\ntitle: {{date}} standup\nvars:\n  team: Platform\n  severity: SEV3\ntags: [a]\n---\n# {{team}}\n"
+	got, err := TemplateVarDefaults(text)
+	if err != nil {
+		t.Fatalf("TemplateVarDefaults() error = %v", err)
+	}
+	if len(got) != 2 || got["team"] != "Platform" || got["severity"] != "SEV3" {
+		t.Errorf("TemplateVarDefaults() = %v", got)
+	}
+
+	if _, err := TemplateVarDefaults("---\nvars:\n  team: [unclosed\n---\n"); err == nil {
+		t.Error("TemplateVarDefaults() accepted invalid YAML in vars")
+	}
+	if got, err := TemplateVarDefaults("# No frontmatter"); err != nil || got != nil {
+		t.Errorf("TemplateVarDefaults() = %v, %v for a template without frontmatter", got, err)
+	}
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// PropertyChange is a property whose value differs between two fetches of a
+// page. Values are shown as JSON; a missing property is empty.
+type PropertyChange struct {
+	Name string `json:"name"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// PropertyChanges compares two sets of page properties, returning the changes
+// sorted by property name.
+func PropertyChanges(old, new map[string]any) []PropertyChange {
+	names := map[string]bool{}
+	for k := range old {
+		names[k] = true
+	}
+	for k := range new {
+		names[k] = true
+	}
+
+	var changes []PropertyChange
+	for name := range names {
+		o, n := propertyJSON(old, name), propertyJSON(new, name)
+		if o != n {
+			changes = append(changes, PropertyChange{Name: name, Old: o, New: n})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+func propertyJSON(props map[string]any, name string) string {
+	v, ok := props[name]
+	if !ok {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPropertyChanges(t *testing.T) {
+	old := map[string]any{"Status": "In progress", "Tags": []any{"api"}, "Owner": "Ada", "Points": 3.0}
+	new := map[string]any{"Status": "Done", "Tags": []any{"api", "db"}, "Owner": "Ada", "Due": "2026-03-01"}
+
+	got := PropertyChanges(old, new)
+	want := []PropertyChange{
+		{Name: "Due", Old: "", New: "2026-03-01"},
+		{Name: "Points", Old: "3", New: ""},
+		{Name: "Status", Old: "In progress", New: "Done"},
+		{Name: "Tags", Old: `["api"]`, New: `["api","db"]`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PropertyChanges() = %+v, want %+v", got, want)
+	}
+
+	if got := PropertyChanges(old, old); len(got) != 0 {
+		t.Errorf("PropertyChanges() of equal properties = %+v", got)
+	}
+}
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// WikiLink is an Obsidian-style [[Target#Heading|Alias]] link.
+type WikiLink struct {
+	Target  string
+	Heading string
+	Alias   string
+}
+
+// Text returns the text the link displays.
+func (w WikiLink) Text() string {
+	if w.Alias != "" {
+		return w.Alias
+	}
+	return w.Target
+}
+
+// wikiLinkRe matches [[...]] links, capturing a preceding "!" so embeds can
+// be skipped.
+var wikiLinkRe = regexp.MustCompile(`(!?)\[\[([^\[\]\n]+)\]\]`)
+
+// RewriteWikiLinks calls fn for every wiki-link in markdown. If fn returns ok,
+// the link is replaced with the returned text. Embeds (![[...]]) and links in
+// code are left alone.
+func RewriteWikiLinks(markdown string, fn func(WikiLink) (string, bool)) string {
+	lines := strings.SplitAfter(markdown, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !strings.Contains(line, "[[") {
+			continue
+		}
+
+		parts := strings.Split(line, "`")
+		for j := 0; j < len(parts); j += 2 {
+			parts[j] = wikiLinkRe.ReplaceAllStringFunc(parts[j], func(match string) string {
+				m := wikiLinkRe.FindStringSubmatch(match)
+				if m[1] == "!" {
+					return match
+				}
+				link := parseWikiLink(m[2])
+				if link.Target == "" {
+					return match
+				}
+				if replacement, ok := fn(link); ok {
+					return replacement
+				}
+				return match
+			})
+		}
+		lines[i] = strings.Join(parts, "`")
+	}
+	return strings.Join(lines, "")
+}
+
+func parseWikiLink(inner string) WikiLink {
+	target, alias, _ := strings.Cut(inner, "|")
+	target, heading, _ := strings.Cut(target, "#")
+	return WikiLink{
+		Target:  strings.TrimSpace(target),
+		Heading: strings.TrimSpace(heading),
+		Alias:   strings.TrimSpace(alias),
+	}
+}
+
+// VaultRoot returns the Obsidian vault containing path (the nearest directory
+// with a .obsidian folder), or "" if it isn't in one.
+func VaultRoot(path string) string {
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return ""
+	}
+	for {
+		if info, err := os.Stat(filepath.Join(dir, ".obsidian")); err == nil && info.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// VaultIndex maps wiki-link targets to Markdown files in a directory tree.
+// Targets match case-insensitively by file name or by path relative to the
+// root, both without the .md extension. When several files share a name the
+// one closest to the root wins, as in Obsidian.
+type VaultIndex map[string]string
+
+// IndexVault indexes the Markdown files under root, skipping dot-directories.
+func IndexVault(root string) (VaultIndex, error) {
+	index := VaultIndex{}
+	depth := map[string]int{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(path), ".md") {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		relKey := strings.ToLower(strings.TrimSuffix(rel, filepath.Ext(rel)))
+		index[relKey] = path
+
+		nameKey := strings.ToLower(strings.TrimSuffix(d.Name(), filepath.Ext(d.Name())))
+		if d, ok := depth[nameKey]; !ok || strings.Count(rel, "/") < d {
+			index[nameKey] = path
+			depth[nameKey] = strings.Count(rel, "/")
+		}
+		return nil
+	})
+	return index, err
+}
+
+// Lookup returns the file a wiki-link target refers to.
+func (v VaultIndex) Lookup(target string) (string, bool) {
+	key := strings.ToLower(strings.TrimSuffix(filepath.ToSlash(target), ".md"))
+	path, ok := v[key]
+	return path, ok
+}
+
+// mentionRe matches <mention-page url="...">Title</mention-page> and the
+// self-closing form.
+var mentionRe = regexp.MustCompile(`<mention-page\s+url="([^"]*)"[^>]*?(?:/>|>(.*?)</mention-page>)`)
+
+// MentionsToWikiLinks replaces page mentions in Notion content with
+// [[Name]] wiki-links. name is called with the mentioned page's ID and the
+// title shown in the mention, which may be empty, and returns the link target.
+func MentionsToWikiLinks(content string, name func(id, title string) string) string {
+	return mentionRe.ReplaceAllStringFunc(content, func(match string) string {
+		m := mentionRe.FindStringSubmatch(match)
+		id, ok := ExtractNotionUUID(m[1])
+		if !ok {
+			return match
+		}
+		target := name(id, strings.TrimSpace(m[2]))
+		if target == "" {
+			return match
+		}
+		return "[[" + target + "]]"
+	})
+}
+
+// WikiLinkMarkdown returns a Markdown link to pageURL for a wiki-link with a
+// heading or alias, which a page mention has nowhere to keep. The heading
+// goes in the URL fragment and the alias in the link text, so
+// NotionLinksToWikiLinks can turn it back into the same wiki-link.
+func WikiLinkMarkdown(link WikiLink, pageURL string) string {
+	if link.Heading != "" {
+		pageURL += "#" + url.PathEscape(link.Heading)
+	}
+	return "[" + link.Text() + "](" + pageURL + ")"
+}
+
+// notionLinkRe matches Markdown links to notion.so pages, capturing a
+// preceding "!" so images can be skipped, and the fragment.
+var notionLinkRe = regexp.MustCompile(`(!?)\[([^\[\]\n]*)\]\(\{*(https://www\.notion\.so/[^)\s#{}]*)(?:#([^)\s{}]*))?\}*\)`)
+
+// NotionLinksToWikiLinks replaces Markdown links to pages with wiki-links,
+// the reverse of WikiLinkMarkdown. name is called with the linked page's ID
+// and returns the link target; links it has no name for are left alone.
+func NotionLinksToWikiLinks(content string, name func(id string) (string, bool)) string {
+	return notionLinkRe.ReplaceAllStringFunc(content, func(match string) string {
+		m := notionLinkRe.FindStringSubmatch(match)
+		if m[1] == "!" {
+			return match
+		}
+		id, ok := ExtractNotionUUID(m[3])
+		if !ok {
+			return match
+		}
+		target, ok := name(id)
+		if !ok {
+			return match
+		}
+
+		link := target
+		if m[4] != "" {
+			heading, err := url.PathUnescape(m[4])
+			if err != nil {
+				heading = m[4]
+			}
+			link += "#" + heading
+		}
+		if text := strings.TrimSpace(m[2]); text != "" && text != target {
+			link += "|" + text
+		}
+		return "[[" + link + "]]"
+	})
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRewriteWikiLinks(t *testing.T) {
+	markdown := "See [[Project Plan]] and [[Notes/Standup#Monday|standup]].\n" +
+		"Embeds ![[diagram.png]] and `[[code]]` stay.\n" +
+		"```\n[[fenced]]\n```\n"
+
+	var got []WikiLink
+	out := RewriteWikiLinks(markdown, func(w WikiLink) (string, bool) {
+		got = append(got, w)
+		return "<" + w.Text() + ">", true
+	})
+
+	want := []WikiLink{
+		{Target: "Project Plan"},
+		{Target: "Notes/Standup", Heading: "Monday", Alias: "standup"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("RewriteWikiLinks() visited %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("link %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	wantOut := "See <Project Plan> and <standup>.\n" +
+		"Embeds ![[diagram.png]] and `[[code]]` stay.\n" +
+		"```\n[[fenced]]\n```\n"
+	if out != wantOut {
+		t.Errorf("RewriteWikiLinks() = %q, want %q", out, wantOut)
+	}
+}
+
+func TestIndexVault(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{"Plan.md", "Notes/Standup.md", "Notes/Plan.md", ".obsidian/workspace.md"} {
+		path := filepath.Join(root, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("# x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	index, err := IndexVault(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"plan":          "Plan.md",
+		"Standup":       "Notes/Standup.md",
+		"notes/plan":    "Notes/Plan.md",
+		"Notes/Plan.md": "Notes/Plan.md",
+	}
+	for target, want := range tests {
+		got, ok := index.Lookup(target)
+		if !ok || got != filepath.Join(root, filepath.FromSlash(want)) {
+			t.Errorf("Lookup(%q) = %q, %v, want %s", target, got, ok, want)
+		}
+	}
+	if _, ok := index.Lookup("workspace"); ok {
+		t.Error("Lookup() found a file inside .obsidian")
+	}
+
+	if got := VaultRoot(filepath.Join(root, "Notes", "Standup.md")); got != root {
+		t.Errorf("VaultRoot() = %q, want %q", got, root)
+	}
+}
+
+func TestMentionsToWikiLinks(t *testing.T) {
+	content := `Before <mention-page url="https://www.notion.so/0123456789abcdef0123456789abcdef">Project Plan</mention-page> and <mention-page url="{{https://www.notion.so/fedcba9876543210fedcba9876543210}}"/> after`
+
+	got := MentionsToWikiLinks(content, func(id, title string) string {
+		if title == "" {
+			return "Fetched " + id[:4]
+		}
+		return title
+	})
+	want := "Before [[Project Plan]] and [[Fetched fedc]] after"
+	if got != want {
+		t.Errorf("MentionsToWikiLinks() = %q, want %q", got, want)
+	}
+}
+
+func TestWikiLinkMarkdownRoundTrip(t *testing.T) {
+	const pageURL = "https://www.notion.so/0123456789abcdef0123456789abcdef"
+	names := func(id string) (string, bool) {
+		return "Project Plan", id == "01234567-89ab-cdef-0123-456789abcdef"
+	}
+
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"[[Project Plan|the plan]]", "[the plan](" + pageURL + ")"},
+		{"[[Project Plan#Next Steps]]", "[Project Plan](" + pageURL + "#Next%20Steps)"},
+		{"[[Project Plan#Next Steps|next]]", "[next](" + pageURL + "#Next%20Steps)"},
+	}
+	for _, tt := range tests {
+		got := RewriteWikiLinks(tt.link, func(link WikiLink) (string, bool) {
+			return WikiLinkMarkdown(link, pageURL), true
+		})
+		if got != tt.want {
+			t.Errorf("WikiLinkMarkdown(%s) = %q, want %q", tt.link, got, tt.want)
+		}
+		if back := NotionLinksToWikiLinks(got, names); back != tt.link {
+			t.Errorf("NotionLinksToWikiLinks(%q) = %q, want %q", got, back, tt.link)
+		}
+	}
+
+	other := "[elsewhere](https://www.notion.so/fedcba9876543210fedcba9876543210)"
+	if got := NotionLinksToWikiLinks(other, names); got != other {
+		t.Errorf("NotionLinksToWikiLinks() rewrote a link to an unknown page: %q", got)
+	}
+}
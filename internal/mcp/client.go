@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/client/transport"
@@ -20,6 +22,7 @@ const (
 type Client struct {
 	mcpClient  *client.Client
 	tokenStore *FileTokenStore
+	dryRun     io.Writer
 }
 
 type ClientOption func(*clientConfig)
@@ -27,6 +30,7 @@ type ClientOption func(*clientConfig)
 type clientConfig struct {
 	endpoint    string
 	accessToken string
+	dryRun      io.Writer
 }
 
 func WithEndpoint(endpoint string) ClientOption {
@@ -41,6 +45,14 @@ func WithAccessToken(token string) ClientOption {
 	}
 }
 
+// WithDryRun makes the client write the tool name and arguments of every
+// call that would change the workspace to w instead of sending it.
+func WithDryRun(w io.Writer) ClientOption {
+	return func(c *clientConfig) {
+		c.dryRun = w
+	}
+}
+
 func NewClient(opts ...ClientOption) (*Client, error) {
 	cfg := &clientConfig{
 		endpoint: DefaultEndpoint,
@@ -76,6 +88,7 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 	return &Client{
 		mcpClient:  client.NewClient(trans),
 		tokenStore: tokenStore,
+		dryRun:     cfg.dryRun,
 	}, nil
 }
 
@@ -146,7 +159,26 @@ func GetOAuthHandler(err error) *transport.OAuthHandler {
 	return nil
 }
 
+// mutatingTools are the tools that change the workspace, which a dry run
+// prints instead of calling.
+var mutatingTools = map[string]bool{
+	"notion-create-pages":   true,
+	"notion-update-page":    true,
+	"notion-move-pages":     true,
+	"notion-duplicate-page": true,
+	"notion-create-comment": true,
+}
+
+// DryRun reports whether the client prints changes instead of making them.
+func (c *Client) DryRun() bool {
+	return c.dryRun != nil
+}
+
 func (c *Client) CallTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	if c.dryRun != nil && mutatingTools[name] {
+		return c.printDryRun(name, args)
+	}
+
 	req := mcp.CallToolRequest{}
 	req.Params.Name = name
 	req.Params.Arguments = args
@@ -154,6 +186,18 @@ func (c *Client) CallTool(ctx context.Context, name string, args map[string]any)
 	return c.mcpClient.CallTool(ctx, req)
 }
 
+// printDryRun writes a call that a dry run skips and returns an empty result.
+func (c *Client) printDryRun(name string, args map[string]any) (*mcp.CallToolResult, error) {
+	payload, err := json.MarshalIndent(args, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Fprintf(c.dryRun, "[dry-run] %s\n%s\n", name, payload); err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText("{}"), nil
+}
+
 func (c *Client) ListTools(ctx context.Context) ([]mcp.Tool, error) {
 	resp, err := c.mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
 	if err != nil {
@@ -164,6 +208,7 @@ func (c *Client) ListTools(ctx context.Context) ([]mcp.Tool, error) {
 
 type SearchOptions struct {
 	ContentSearchMode string // "workspace_search" or "ai_search" or "" (auto)
+	DataSourceURL     string // search only the rows of this data source (collection://...)
 }
 
 func (c *Client) Search(ctx context.Context, query string, opts *SearchOptions) (*SearchResponse, error) {
@@ -173,6 +218,9 @@ func (c *Client) Search(ctx context.Context, query string, opts *SearchOptions)
 	if opts != nil && opts.ContentSearchMode != "" {
 		args["content_search_mode"] = opts.ContentSearchMode
 	}
+	if opts != nil && opts.DataSourceURL != "" {
+		args["data_source_url"] = opts.DataSourceURL
+	}
 	result, err := c.CallTool(ctx, "notion-search", args)
 	if err != nil {
 		return nil, err
@@ -191,20 +239,36 @@ func (c *Client) Search(ctx context.Context, query string, opts *SearchOptions)
 }
 
 type FetchResult struct {
-	Content string
-	Title   string
-	URL     string
+	Content    string
+	Title      string
+	URL        string
+	Type       string
+	ParentType string // "page", "database", "data-source" or "workspace"
+	ParentID   string
+	Archived   bool
+
+	LastEditedTime time.Time
 }
 
 type fetchResponse struct {
 	Metadata struct {
-		Type string `json:"type"`
+		Type     string `json:"type"`
+		Archived bool   `json:"archived"`
+		InTrash  bool   `json:"in_trash"`
+
+		LastEditedTime string `json:"last_edited_time"`
 	} `json:"metadata"`
 	Title string `json:"title"`
 	URL   string `json:"url"`
 	Text  string `json:"text"`
 }
 
+// parentTagRe matches the direct parent reference in fetched page content,
+// e.g. <parent-page url="{{https://www.notion.so/...}}" title="..."/>.
+var parentTagRe = regexp.MustCompile(`<parent-(page|database|data-source)\s+url="\{*([^"}]+)\}*"`)
+
+var notionIDRe = regexp.MustCompile(`[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}`)
+
 func (c *Client) Fetch(ctx context.Context, id string) (*FetchResult, error) {
 	result, err := c.CallTool(ctx, "notion-fetch", map[string]any{
 		"id": id,
@@ -218,12 +282,124 @@ func (c *Client) Fetch(ctx context.Context, id string) (*FetchResult, error) {
 
 	text := extractText(result)
 
+	fetched := &FetchResult{Content: text}
 	var resp fetchResponse
 	if err := json.Unmarshal([]byte(text), &resp); err == nil && resp.Text != "" {
-		return &FetchResult{Content: resp.Text, Title: resp.Title, URL: resp.URL}, nil
+		fetched = &FetchResult{
+			Content:  resp.Text,
+			Title:    resp.Title,
+			URL:      resp.URL,
+			Type:     resp.Metadata.Type,
+			Archived: resp.Metadata.Archived || resp.Metadata.InTrash,
+		}
+		fetched.LastEditedTime, _ = time.Parse(time.RFC3339, resp.Metadata.LastEditedTime)
 	}
+	fetched.ParentType, fetched.ParentID = parseParent(fetched.Content)
+	if fetched.LastEditedTime.IsZero() {
+		fetched.LastEditedTime = parseLastEdited(fetched.Content)
+	}
+
+	return fetched, nil
+}
+
+var lastEditedRe = regexp.MustCompile(`"?last[_-]edited[_-]time"?\s*[:=]\s*"([^"]+)"`)
+
+// parseLastEdited finds a page's last edited time in fetched content, which
+// may appear as a JSON property or a tag attribute.
+func parseLastEdited(content string) time.Time {
+	m := lastEditedRe.FindStringSubmatch(content)
+	if m == nil {
+		return time.Time{}
+	}
+	t, _ := time.Parse(time.RFC3339, m[1])
+	return t
+}
+
+var contentTagRe = regexp.MustCompile(`(?s)<content>\n?(.*?)\n?</content>`)
 
-	return &FetchResult{Content: text}, nil
+// Body returns the page content inside the <content> tag, in Notion-flavoured
+// Markdown. This is the text that content edits operate on.
+func (r *FetchResult) Body() string {
+	if m := contentTagRe.FindStringSubmatch(r.Content); m != nil {
+		return m[1]
+	}
+	return r.Content
+}
+
+var propertiesTagRe = regexp.MustCompile(`(?s)<properties>\s*(.*?)\s*</properties>`)
+
+// Properties returns the page's property values from the <properties> tag,
+// or nil if the content has none.
+func (r *FetchResult) Properties() map[string]any {
+	m := propertiesTagRe.FindStringSubmatch(r.Content)
+	if m == nil {
+		return nil
+	}
+	var props map[string]any
+	if err := json.Unmarshal([]byte(m[1]), &props); err != nil {
+		return nil
+	}
+	return props
+}
+
+// ChildRef is a child page or database embedded in a page's content.
+type ChildRef struct {
+	Type  string // "page" or "database"
+	ID    string
+	URL   string
+	Title string
+	Icon  string
+}
+
+// childTagRe matches <page url="...">Title</page> and <database ...> blocks
+// in page content, and their self-closing forms.
+var childTagRe = regexp.MustCompile(`<(page|database)\s([^>]*?)(?:/>|>([^<]*)</(?:page|database)>)`)
+
+var (
+	urlAttrRe  = regexp.MustCompile(`\burl="\{*([^"}]+)\}*"`)
+	iconAttrRe = regexp.MustCompile(`\bicon="([^"]*)"`)
+)
+
+// Children returns the child pages and databases in the page content, in the
+// order they appear.
+func (r *FetchResult) Children() []ChildRef {
+	var children []ChildRef
+	for _, m := range childTagRe.FindAllStringSubmatch(r.Body(), -1) {
+		urlMatch := urlAttrRe.FindStringSubmatch(m[2])
+		if urlMatch == nil {
+			continue
+		}
+		child := ChildRef{
+			Type:  m[1],
+			URL:   urlMatch[1],
+			Title: strings.TrimSpace(m[3]),
+		}
+		if ids := notionIDRe.FindAllString(child.URL, -1); len(ids) > 0 {
+			child.ID = ids[len(ids)-1]
+		}
+		if icon := iconAttrRe.FindStringSubmatch(m[2]); icon != nil {
+			child.Icon = icon[1]
+		}
+		children = append(children, child)
+	}
+	return children
+}
+
+// parseParent extracts the direct parent of a fetched page from its content.
+// Further ancestors use different tag names (ancestor-2-page, ...) and are ignored.
+func parseParent(content string) (parentType, parentID string) {
+	m := parentTagRe.FindStringSubmatch(content)
+	if m == nil {
+		if strings.Contains(content, "<parent-workspace") {
+			return "workspace", ""
+		}
+		return "", ""
+	}
+	ids := notionIDRe.FindAllString(m[2], -1)
+	if len(ids) == 0 {
+		return m[1], m[2]
+	}
+	return m[1], ids[len(ids)-1]
 }
 
 type CreatePageRequest struct {
@@ -231,7 +407,9 @@ type CreatePageRequest struct {
 	ParentDatabaseID string
 	Title            string
 	Content          string
-	Properties       map[string]string
+	Properties       map[string]any
+	Icon             string
+	Cover            string
 }
 
 type CreatePageResponse struct {
@@ -240,51 +418,100 @@ type CreatePageResponse struct {
 }
 
 func (c *Client) CreatePage(ctx context.Context, req CreatePageRequest) (*CreatePageResponse, error) {
-	props := map[string]any{}
-	for k, v := range req.Properties {
-		props[k] = v
+	text, err := c.createPages(ctx, []CreatePageRequest{req})
+	if err != nil {
+		return nil, err
 	}
-	props["title"] = req.Title
 
-	pageSpec := map[string]any{
-		"properties": props,
+	var resp CreatePageResponse
+	if err := json.Unmarshal([]byte(text), &resp); err == nil && resp.URL != "" {
+		return &resp, nil
 	}
 
-	if req.Content != "" {
-		pageSpec["content"] = req.Content
+	url := extractURLFromText(text)
+	return &CreatePageResponse{URL: url}, nil
+}
+
+// CreatePages creates several pages in one call. They must all have the
+// parent of the first. The responses are in request order; when the server's
+// reply can't be matched up with the requests, they have empty IDs and URLs.
+func (c *Client) CreatePages(ctx context.Context, reqs []CreatePageRequest) ([]CreatePageResponse, error) {
+	text, err := c.createPages(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	resps := make([]CreatePageResponse, len(reqs))
+	var parsed struct {
+		Pages []CreatePageResponse `json:"pages"`
+	}
+	if err := json.Unmarshal([]byte(text), &parsed); err == nil && len(parsed.Pages) == len(reqs) {
+		copy(resps, parsed.Pages)
+		return resps, nil
+	}
+	var list []CreatePageResponse
+	if err := json.Unmarshal([]byte(text), &list); err == nil && len(list) == len(reqs) {
+		copy(resps, list)
+		return resps, nil
+	}
+	if urls := pageURLRe.FindAllString(text, -1); len(urls) == len(reqs) {
+		for i, url := range urls {
+			resps[i].URL = url
+		}
+	}
+	return resps, nil
+}
+
+var pageURLRe = regexp.MustCompile(`https://www\.notion\.so/[^\s"')>]+`)
+
+// createPages calls notion-create-pages and returns the text of the reply.
+func (c *Client) createPages(ctx context.Context, reqs []CreatePageRequest) (string, error) {
+	pages := make([]any, 0, len(reqs))
+	for _, req := range reqs {
+		props := map[string]any{}
+		for k, v := range req.Properties {
+			props[k] = v
+		}
+		props["title"] = req.Title
+
+		pageSpec := map[string]any{
+			"properties": props,
+		}
+
+		if req.Content != "" {
+			pageSpec["content"] = req.Content
+		}
+		if req.Icon != "" {
+			pageSpec["icon"] = req.Icon
+		}
+		if req.Cover != "" {
+			pageSpec["cover"] = req.Cover
+		}
+		pages = append(pages, pageSpec)
 	}
 
 	args := map[string]any{
-		"pages": []any{pageSpec},
+		"pages": pages,
 	}
 
-	if req.ParentPageID != "" {
+	if reqs[0].ParentPageID != "" {
 		args["parent"] = map[string]any{
-			"page_id": req.ParentPageID,
+			"page_id": reqs[0].ParentPageID,
 		}
-	} else if req.ParentDatabaseID != "" {
+	} else if reqs[0].ParentDatabaseID != "" {
 		args["parent"] = map[string]any{
-			"data_source_id": req.ParentDatabaseID,
+			"data_source_id": reqs[0].ParentDatabaseID,
 		}
 	}
 
 	result, err := c.CallTool(ctx, "notion-create-pages", args)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	if err := checkToolError(result); err != nil {
-		return nil, err
+		return "", err
 	}
-
-	text := extractText(result)
-
-	var resp CreatePageResponse
-	if err := json.Unmarshal([]byte(text), &resp); err == nil && resp.URL != "" {
-		return &resp, nil
-	}
-
-	url := extractURLFromText(text)
-	return &CreatePageResponse{URL: url}, nil
+	return extractText(result), nil
 }
 
 func extractURLFromText(text string) string {
@@ -316,6 +543,136 @@ func (c *Client) ResolveDataSourceID(ctx context.Context, id string) (string, er
 	return id, nil // fallback to original ID
 }
 
+// DuplicatePage copies a page. Duplication completes asynchronously, so the
+// returned page may not be fetchable immediately.
+func (c *Client) DuplicatePage(ctx context.Context, pageID string) (*CreatePageResponse, error) {
+	result, err := c.CallTool(ctx, "notion-duplicate-page", map[string]any{
+		"page_id": pageID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkToolError(result); err != nil {
+		return nil, err
+	}
+
+	text := extractText(result)
+
+	var resp CreatePageResponse
+	if err := json.Unmarshal([]byte(text), &resp); err != nil || resp.URL == "" {
+		resp.URL = extractURLFromText(text)
+	}
+	if resp.ID == "" {
+		ids := notionIDRe.FindAllString(resp.URL, -1)
+		if len(ids) > 0 {
+			resp.ID = ids[len(ids)-1]
+		}
+	}
+	if resp.ID == "" {
+		var idResp struct {
+			PageID string `json:"page_id"`
+		}
+		if err := json.Unmarshal([]byte(text), &idResp); err == nil {
+			resp.ID = idResp.PageID
+		}
+	}
+
+	return &resp, nil
+}
+
+// FetchSchema returns the property schema of a database or data source, keyed by
+// property name.
+func (c *Client) FetchSchema(ctx context.Context, id string) (map[string]PropertySchema, error) {
+	result, err := c.Fetch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSchema(result.Content)
+}
+
+// ParseSchema extracts the property schema from the <data-source-state> block
+// of fetched database content.
+func ParseSchema(content string) (map[string]PropertySchema, error) {
+	start := strings.Index(content, "<data-source-state>")
+	if start == -1 {
+		return nil, fmt.Errorf("no schema found in database content")
+	}
+	end := strings.Index(content[start:], "</data-source-state>")
+	if end == -1 {
+		return nil, fmt.Errorf("no schema found in database content")
+	}
+
+	stateJSON := strings.TrimSpace(content[start+len("<data-source-state>") : start+end])
+	var state struct {
+		Schema map[string]PropertySchema `json:"schema"`
+	}
+	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+
+	schema := make(map[string]PropertySchema, len(state.Schema))
+	for key, prop := range state.Schema {
+		if prop.Name == "" {
+			prop.Name = key
+		}
+		schema[prop.Name] = prop
+	}
+	return schema, nil
+}
+
+type MovePagesRequest struct {
+	PageIDs          []string
+	ParentPageID     string
+	ParentDatabaseID string // data source ID
+}
+
+func (c *Client) MovePages(ctx context.Context, req MovePagesRequest) error {
+	ids := make([]any, 0, len(req.PageIDs))
+	for _, id := range req.PageIDs {
+		ids = append(ids, id)
+	}
+
+	args := map[string]any{
+		"page_or_database_ids": ids,
+	}
+
+	if req.ParentPageID != "" {
+		args["new_parent"] = map[string]any{
+			"page_id": req.ParentPageID,
+		}
+	} else if req.ParentDatabaseID != "" {
+		args["new_parent"] = map[string]any{
+			"data_source_id": req.ParentDatabaseID,
+		}
+	} else {
+		args["new_parent"] = map[string]any{
+			"type": "workspace",
+		}
+	}
+
+	result, err := c.CallTool(ctx, "notion-move-pages", args)
+	if err != nil {
+		return err
+	}
+	return checkToolError(result)
+}
+
+// SetArchived moves a page (or database entry) to the trash, or restores it.
+// The archived argument isn't part of the documented tool schema, so callers
+// should check the result; cli.SetArchived does.
+func (c *Client) SetArchived(ctx context.Context, pageID string, archived bool) error {
+	result, err := c.CallTool(ctx, "notion-update-page", map[string]any{
+		"page_id":    pageID,
+		"command":    "update_properties",
+		"properties": map[string]any{},
+		"archived":   archived,
+	})
+	if err != nil {
+		return err
+	}
+	return checkToolError(result)
+}
+
 type UpdatePageRequest struct {
 	PageID  string
 	Command string // "replace_content", "replace_content_range", "insert_content_after", "update_properties"
@@ -327,8 +684,11 @@ type UpdatePageRequest struct {
 	Selection string
 	NewStr    string
 
-	// For update_properties
+	// For update_properties. Icon is an emoji or image URL; Cover is an
+	// image URL.
 	Properties map[string]any
+	Icon       string
+	Cover      string
 }
 
 func (c *Client) UpdatePage(ctx context.Context, req UpdatePageRequest) error {
@@ -344,7 +704,17 @@ func (c *Client) UpdatePage(ctx context.Context, req UpdatePageRequest) error {
 		data["selection_with_ellipsis"] = req.Selection
 		data["new_str"] = req.NewStr
 	case "update_properties":
-		data["properties"] = req.Properties
+		props := req.Properties
+		if props == nil {
+			props = map[string]any{}
+		}
+		data["properties"] = props
+		if req.Icon != "" {
+			data["icon"] = req.Icon
+		}
+		if req.Cover != "" {
+			data["cover"] = req.Cover
+		}
 	}
 
 	result, err := c.CallTool(ctx, "notion-update-page", data)
@@ -427,6 +797,34 @@ func (c *Client) CreateComment(ctx context.Context, req CreateCommentRequest) (*
 	return &comment, nil
 }
 
+// CurrentUser returns the name of the user the client is authenticated as.
+func (c *Client) CurrentUser(ctx context.Context) (string, error) {
+	result, err := c.CallTool(ctx, "notion-get-users", map[string]any{"user_id": "self"})
+	if err != nil {
+		return "", err
+	}
+	if err := checkToolError(result); err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		Name    string `json:"name"`
+		Results []struct {
+			Name string `json:"name"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(extractText(result)), &resp); err != nil {
+		return "", fmt.Errorf("parse user: %w", err)
+	}
+	if resp.Name == "" && len(resp.Results) > 0 {
+		resp.Name = resp.Results[0].Name
+	}
+	if resp.Name == "" {
+		return "", fmt.Errorf("no user name in response")
+	}
+	return resp.Name, nil
+}
+
 // staticTokenStore provides a token from a fixed string (for CI/env var usage)
 type staticTokenStore struct {
 	token string
@@ -155,6 +155,17 @@ type File struct {
 	ExpiryTime time.Time `json:"expiry_time,omitempty"`
 }
 
+// PropertySchema describes a database property as reported in a data source's state.
+type PropertySchema struct {
+	Name    string           `json:"name"`
+	Type    string           `json:"type"`
+	Options []PropertyOption `json:"options,omitempty"`
+}
+
+type PropertyOption struct {
+	Name string `json:"name"`
+}
+
 // Search types
 
 type SearchResult struct {
@@ -164,6 +175,7 @@ type SearchResult struct {
 	URL        string `json:"url,omitempty"`
 	ObjectType string `json:"object_type,omitempty"`
 	Type       string `json:"type,omitempty"`
+	Archived   bool   `json:"archived,omitempty"`
 }
 
 type SearchResponse struct {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
@@ -21,9 +22,13 @@ func PrintPages(pages []Page, asJSON bool) error {
 
 	table := NewTable("ID", "TITLE", "LAST EDITED", "URL")
 	for _, p := range pages {
+		title := Truncate(p.Title, 50)
+		if p.Archived {
+			title += " (archived)"
+		}
 		table.AddRow(
 			TruncateID(p.ID),
-			Truncate(p.Title, 50),
+			title,
 			formatTime(p.LastEditedTime),
 			p.URL,
 		)
@@ -76,6 +81,11 @@ func PrintPage(page Page, asJSON bool) error {
 	return nil
 }
 
+// PrintPageAction prints a page and the action taken on it as JSON.
+func PrintPageAction(result PageAction) error {
+	return printJSON(result)
+}
+
 func PrintDatabases(dbs []Database, asJSON bool) error {
 	if asJSON {
 		return printJSON(dbs)
@@ -123,6 +133,115 @@ func PrintSearchResults(results []SearchResult, asJSON bool) error {
 	return nil
 }
 
+func PrintPageMoves(moves []PageMove, asJSON bool) error {
+	if asJSON {
+		return printJSON(moves)
+	}
+
+	table := NewTable("ID", "TITLE", "OLD PARENT", "NEW PARENT")
+	for _, m := range moves {
+		table.AddRow(
+			TruncateID(m.ID),
+			Truncate(m.Title, 40),
+			TruncateID(m.OldParent),
+			TruncateID(m.NewParent),
+		)
+	}
+	table.Render()
+	return nil
+}
+
+// PrintArchiveResults prints what archive or restore did to each page as JSON.
+func PrintArchiveResults(results []ArchiveResult) error {
+	return printJSON(results)
+}
+
+func PrintSyncResults(results []SyncResult, asJSON bool) error {
+	if asJSON {
+		return printJSON(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Println("Nothing to sync.")
+		return nil
+	}
+
+	table := NewTable("ACTION", "FILE", "ID", "TITLE")
+	for _, r := range results {
+		table.AddRow(
+			r.Action,
+			r.File,
+			TruncateID(r.ID),
+			Truncate(r.Title, 40),
+		)
+	}
+	table.Render()
+	return nil
+}
+
+// PrintPageTree prints a page hierarchy as an indented tree, like tree(1).
+func PrintPageTree(root *PageTreeNode, asJSON bool) error {
+	if asJSON {
+		return printJSON(root)
+	}
+
+	idStyle := color.New(color.Faint)
+	var pages, databases int
+	var printNode func(node *PageTreeNode, prefix, connector, childPrefix string)
+	printNode = func(node *PageTreeNode, prefix, connector, childPrefix string) {
+		if node != root {
+			if node.Type == "database" {
+				databases++
+			} else {
+				pages++
+			}
+		}
+
+		icon := node.Icon
+		if icon == "" {
+			icon = "📄"
+			if node.Type == "database" {
+				icon = "📊"
+			}
+		}
+		title := node.Title
+		if title == "" {
+			title = "Untitled"
+		}
+
+		fmt.Print(prefix + connector + icon + " " + title)
+		if node.ID != "" {
+			_, _ = idStyle.Print(" " + node.ID)
+		}
+		fmt.Println()
+
+		for i, child := range node.Children {
+			if i == len(node.Children)-1 {
+				printNode(child, prefix+childPrefix, "└── ", "    ")
+			} else {
+				printNode(child, prefix+childPrefix, "├── ", "│   ")
+			}
+		}
+	}
+	printNode(root, "", "", "")
+
+	fmt.Println()
+	fmt.Printf("%d %s, %d %s\n", pages, Plural("page", pages), databases, Plural("database", databases))
+	return nil
+}
+
+// Plural returns noun, or its plural when n isn't 1: "entry" becomes
+// "entries", other nouns get an "s".
+func Plural(noun string, n int) string {
+	if n == 1 {
+		return noun
+	}
+	if stem, ok := strings.CutSuffix(noun, "y"); ok && stem != "" && !strings.ContainsAny(stem[len(stem)-1:], "aeiou") {
+		return stem + "ies"
+	}
+	return noun + "s"
+}
+
 func PrintComments(comments []Comment, asJSON bool) error {
 	if asJSON {
 		return printJSON(comments)
@@ -156,16 +275,37 @@ func PrintError(err error) {
 	_, _ = fmt.Fprintln(os.Stderr, err.Error())
 }
 
+// dryRun marks success messages as simulated; see SetDryRun.
+var dryRun bool
+
+// SetDryRun prefixes success messages with [dry-run], since nothing they
+// report has actually happened.
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+}
+
 func PrintSuccess(message string) {
+	if dryRun {
+		message = "[dry-run] " + message
+	}
 	successStyle := color.New(color.FgGreen)
 	_, _ = successStyle.Print("✓ ")
 	fmt.Println(message)
 }
 
+// PrintWarning writes to stderr, like PrintError, so that warnings never end
+// up in --json output.
 func PrintWarning(message string) {
 	warnStyle := color.New(color.FgYellow)
-	_, _ = warnStyle.Print("⚠ ")
-	fmt.Println(message)
+	_, _ = warnStyle.Fprint(os.Stderr, "⚠ ")
+	_, _ = fmt.Fprintln(os.Stderr, message)
+}
+
+// PrintProgress reports progress on a long operation. It writes to stderr so
+// it doesn't mix with --json output.
+func PrintProgress(message string) {
+	progressStyle := color.New(color.Faint)
+	_, _ = progressStyle.Fprintln(os.Stderr, message)
 }
 
 func PrintInfo(message string) {
@@ -173,6 +313,29 @@ func PrintInfo(message string) {
 	_, _ = infoStyle.Println(message)
 }
 
+// PrintDiff prints a unified diff, coloured when stdout is a terminal.
+func PrintDiff(diff string) {
+	addStyle := color.New(color.FgGreen)
+	delStyle := color.New(color.FgRed)
+	hunkStyle := color.New(color.FgCyan)
+	headerStyle := color.New(color.Bold)
+
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			_, _ = headerStyle.Println(line)
+		case strings.HasPrefix(line, "@@"):
+			_, _ = hunkStyle.Println(line)
+		case strings.HasPrefix(line, "+"):
+			_, _ = addStyle.Println(line)
+		case strings.HasPrefix(line, "-"):
+			_, _ = delStyle.Println(line)
+		default:
+			fmt.Println(line)
+		}
+	}
+}
+
 type UserError struct {
 	Message string
 }
@@ -181,6 +344,16 @@ func (e *UserError) Error() string {
 	return e.Message
 }
 
+// ExitError requests a non-zero exit status without printing an error message,
+// for commands whose exit code carries meaning (e.g. a diff found changes).
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
 func printJSON(v any) error {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
@@ -7,6 +7,11 @@ import (
 	"golang.org/x/net/html"
 )
 
+// NotionToMarkdown converts Notion-flavoured page content to plain Markdown.
+func NotionToMarkdown(content string) string {
+	return notionToMarkdown(content)
+}
+
 // notionToMarkdown converts Notion's XML-like content to Markdown.
 // It uses an HTML parser which is lenient with malformed markup.
 func notionToMarkdown(content string) string {
@@ -54,7 +59,7 @@ func notionToMarkdown(content string) string {
 	result := out.String()
 
 	// Clean up excess blank lines
-	result = regexp.MustCompile(`\n{3,}`).ReplaceAllString(result, "\n\n")
+	result = blankLinesRe.ReplaceAllString(result, "\n\n")
 
 	return strings.TrimSpace(result)
 }
@@ -62,6 +67,8 @@ func notionToMarkdown(content string) string {
 type renderContext struct {
 	out     *strings.Builder
 	inQuote bool
+	// depth is how many tabs Notion indents the current block's children by.
+	depth int
 }
 
 func (ctx *renderContext) renderNode(n *html.Node) {
@@ -83,6 +90,8 @@ var (
 	colorAnnotationRe = regexp.MustCompile(`\s*\{color="[^"]+"\}`)
 	slackLinkRe       = regexp.MustCompile(`\[([^\]]+)\]\(\{\{slackChannel://[^}]+\}\}\)`)
 	notionURLRe       = regexp.MustCompile(`\{\{([^}]+)\}\}`)
+	blankLinesRe      = regexp.MustCompile(`\n{3,}`)
+	inlineMathRe      = regexp.MustCompile("\\$`([^`\n]+)`\\$")
 )
 
 func (ctx *renderContext) renderText(text string) {
@@ -95,18 +104,25 @@ func (ctx *renderContext) renderText(text string) {
 	// Clean remaining {{url}} wrappers in markdown links
 	text = notionURLRe.ReplaceAllString(text, "$1")
 
-	if ctx.inQuote {
-		// Add quote prefix to each line
-		lines := strings.Split(text, "\n")
-		for i, line := range lines {
-			if i > 0 {
-				ctx.out.WriteString("\n> ")
-			}
-			ctx.out.WriteString(line)
+	// Inline equations: $`x`$ -> $x$
+	text = inlineMathRe.ReplaceAllString(text, "$$${1}$$")
+
+	if ctx.depth > 0 {
+		text = stripIndent(text, ctx.depth)
+	}
+	ctx.out.WriteString(text)
+}
+
+// stripIndent removes up to depth leading tabs from each line after the
+// first, undoing the indentation Notion gives a block's children.
+func stripIndent(text string, depth int) string {
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		for j := 0; j < depth && strings.HasPrefix(lines[i], "\t"); j++ {
+			lines[i] = lines[i][1:]
 		}
-	} else {
-		ctx.out.WriteString(text)
 	}
+	return strings.Join(lines, "\n")
 }
 
 func (ctx *renderContext) renderElement(n *html.Node) {
@@ -117,6 +133,12 @@ func (ctx *renderContext) renderElement(n *html.Node) {
 		ctx.renderColumns(n)
 	case "column":
 		ctx.renderColumn(n)
+	case "details":
+		ctx.renderDetails(n)
+	case "summary":
+		// Rendered by renderDetails
+	case "table":
+		ctx.renderTable(n)
 	case "page":
 		ctx.renderPageLink(n)
 	case "database":
@@ -178,6 +200,55 @@ func (ctx *renderContext) renderChildren(n *html.Node) {
 	}
 }
 
+// renderBlock renders the children of a container block, whose lines Notion
+// indents by indent more tabs than the container itself.
+func (ctx *renderContext) renderBlock(n *html.Node, indent int) string {
+	var out strings.Builder
+	sub := &renderContext{out: &out, inQuote: ctx.inQuote, depth: ctx.depth + indent}
+	sub.renderChildren(n)
+	body := blankLinesRe.ReplaceAllString(out.String(), "\n\n")
+	return strings.TrimRight(strings.TrimLeft(body, "\n"), " \t\n")
+}
+
+// renderInline renders the children of n on a single line.
+func (ctx *renderContext) renderInline(n *html.Node) string {
+	var out strings.Builder
+	sub := &renderContext{out: &out, inQuote: true, depth: ctx.depth}
+	sub.renderChildren(n)
+	return strings.TrimSpace(out.String())
+}
+
+// Admonition is a GitHub alert type and the callout it corresponds to.
+type Admonition struct {
+	Type  string
+	Icon  string
+	Color string
+}
+
+// Admonitions lists the GitHub alerts (> [!NOTE]) and the callout icon and
+// colour each is shown as in Notion.
+var Admonitions = []Admonition{
+	{Type: "NOTE", Icon: "ℹ️", Color: "blue_bg"},
+	{Type: "TIP", Icon: "💡", Color: "green_bg"},
+	{Type: "IMPORTANT", Icon: "☝️", Color: "purple_bg"},
+	{Type: "WARNING", Icon: "⚠️", Color: "yellow_bg"},
+	{Type: "CAUTION", Icon: "🛑", Color: "red_bg"},
+}
+
+// calloutAdmonition returns the alert a callout was created from, matching
+// both icon and colour so that other callouts keep their own icon.
+func calloutAdmonition(icon, color string) (Admonition, bool) {
+	icon = strings.TrimSuffix(icon, "\ufe0f")
+	for _, a := range Admonitions {
+		if strings.TrimSuffix(a.Icon, "\ufe0f") == icon && a.Color == color {
+			return a, true
+		}
+	}
+	return Admonition{}, false
+}
+
+// renderCallout renders a callout as a quote: a GitHub alert when it matches
+// one, otherwise a quote that starts with the callout's icon.
 func (ctx *renderContext) renderCallout(n *html.Node) {
 	icon := getAttr(n, "icon")
 
@@ -186,33 +257,100 @@ func (ctx *renderContext) renderCallout(n *html.Node) {
 		icon = "💡"
 	}
 
-	ctx.out.WriteString("\n> " + icon + " ")
-
-	// Render children in quote context
 	oldQuote := ctx.inQuote
 	ctx.inQuote = true
-	ctx.renderChildren(n)
+	lines := strings.Split(ctx.renderBlock(n, 1), "\n")
 	ctx.inQuote = oldQuote
 
+	if a, ok := calloutAdmonition(icon, getAttr(n, "color")); ok {
+		lines = append([]string{"[!" + a.Type + "]"}, lines...)
+	} else {
+		lines[0] = strings.TrimSpace(icon + " " + lines[0])
+	}
+
 	ctx.out.WriteString("\n")
+	for _, line := range lines {
+		ctx.out.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+	}
 }
 
+// renderDetails renders a toggle as an HTML <details> block, which GitHub
+// shows as a toggle too.
+func (ctx *renderContext) renderDetails(n *html.Node) {
+	summary := ""
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "summary" {
+			summary = ctx.renderInline(c)
+			break
+		}
+	}
+
+	ctx.out.WriteString("\n<details>\n<summary>" + summary + "</summary>\n\n")
+	if body := ctx.renderBlock(n, 1); body != "" {
+		ctx.out.WriteString(body + "\n\n")
+	}
+	ctx.out.WriteString("</details>\n")
+}
+
+// renderColumns keeps the <columns> and <column> tags, since Markdown has no
+// syntax for columns, with blank lines so their content is still Markdown.
 func (ctx *renderContext) renderColumns(n *html.Node) {
-	// Just render children - columns will add separators
-	ctx.renderChildren(n)
+	ctx.out.WriteString("\n<columns>\n")
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.Data == "column" {
+			ctx.out.WriteString("<column>\n\n" + ctx.renderBlock(c, 2) + "\n\n</column>\n")
+		}
+	}
+	ctx.out.WriteString("</columns>\n")
 }
 
 func (ctx *renderContext) renderColumn(n *html.Node) {
-	// Collect column content
-	var colOut strings.Builder
-	colCtx := &renderContext{out: &colOut}
-	colCtx.renderChildren(n)
+	ctx.out.WriteString("\n" + ctx.renderBlock(n, 1) + "\n")
+}
+
+// renderTable renders a table as a GitHub table. GitHub tables always have a
+// header, so a table without header-row="true" gets an empty one, which
+// converting back to Notion drops again.
+func (ctx *renderContext) renderTable(n *html.Node) {
+	var rows [][]string
+	width := 0
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "tr" {
+			var row []string
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode && (c.Data == "td" || c.Data == "th") {
+					cell := strings.ReplaceAll(ctx.renderInline(c), "|", `\|`)
+					row = append(row, strings.ReplaceAll(cell, "\n", "<br>"))
+				}
+			}
+			rows = append(rows, row)
+			width = max(width, len(row))
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	if len(rows) == 0 || width == 0 {
+		return
+	}
+
+	if getAttr(n, "header-row") != "true" {
+		rows = append([][]string{make([]string, width)}, rows...)
+	}
 
-	// Dedent and add to output
-	content := dedentContent(colOut.String())
-	ctx.out.WriteString("\n")
-	ctx.out.WriteString(content)
 	ctx.out.WriteString("\n")
+	for i, row := range rows {
+		for len(row) < width {
+			row = append(row, "")
+		}
+		ctx.out.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			ctx.out.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+		}
+	}
 }
 
 func (ctx *renderContext) renderPageLink(n *html.Node) {
@@ -302,51 +440,3 @@ func getTextContent(n *html.Node) string {
 	walk(n)
 	return strings.TrimSpace(text.String())
 }
-
-// dedentContent removes common leading whitespace from all lines
-func dedentContent(content string) string {
-	lines := strings.Split(content, "\n")
-	if len(lines) == 0 {
-		return content
-	}
-
-	// Find minimum indentation (excluding empty lines)
-	minIndent := -1
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-		indent := 0
-		for _, ch := range line {
-			if ch == ' ' || ch == '\t' {
-				indent++
-			} else {
-				break
-			}
-		}
-		if minIndent == -1 || indent < minIndent {
-			minIndent = indent
-		}
-	}
-
-	if minIndent <= 0 {
-		return strings.TrimSpace(content)
-	}
-
-	// Remove common indentation
-	var result []string
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
-			result = append(result, "")
-			continue
-		}
-		runes := []rune(line)
-		if len(runes) >= minIndent {
-			result = append(result, string(runes[minIndent:]))
-		} else {
-			result = append(result, strings.TrimSpace(line))
-		}
-	}
-
-	return strings.TrimSpace(strings.Join(result, "\n"))
-}
@@ -15,6 +15,13 @@ type Page struct {
 	Content        string
 }
 
+// PageAction is a page along with what a create did with it: "created", or
+// "skipped" or "updated" when the page already existed.
+type PageAction struct {
+	Page
+	Action string
+}
+
 type Database struct {
 	ID             string
 	Title          string
@@ -42,3 +49,39 @@ type Comment struct {
 	CreatedBy      string
 	Content        string
 }
+
+type PageMove struct {
+	ID        string
+	Title     string
+	OldParent string
+	NewParent string
+}
+
+// ArchiveResult is what happened to one page given to archive or restore:
+// Action is "archived", "restored" or "failed", with Error set on failure.
+type ArchiveResult struct {
+	ID     string
+	Title  string
+	URL    string
+	Action string
+	Error  string
+}
+
+type SyncResult struct {
+	File   string
+	Action string
+	ID     string
+	Title  string
+	Icon   string
+	URL    string
+}
+
+// PageTreeNode is a page or database in a page hierarchy.
+type PageTreeNode struct {
+	ID       string
+	Title    string
+	Icon     string
+	Type     string
+	URL      string
+	Children []*PageTreeNode
+}
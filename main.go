@@ -1,11 +1,13 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/alecthomas/kong"
 	"github.com/lox/notion-cli/cmd"
 	"github.com/lox/notion-cli/internal/cli"
+	"github.com/lox/notion-cli/internal/output"
 )
 
 var version = "dev"
@@ -19,7 +21,12 @@ func main() {
 		kong.Vars{"version": version},
 	)
 	cli.SetAccessToken(c.Token)
+	cli.SetDryRun(c.DryRun)
 	err := ctx.Run(&cmd.Context{Token: c.Token})
+	var exitErr *output.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.Code)
+	}
 	ctx.FatalIfErrorf(err)
 	os.Exit(0)
 }